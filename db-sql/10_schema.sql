@@ -49,6 +49,9 @@ CREATE TABLE `reservation_slots` (
 CREATE TABLE `tags` (
   `id` BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
   `name` VARCHAR(255) NOT NULL,
+  `retired` TINYINT(1) NOT NULL DEFAULT 0,
+  -- 親のタグ(カテゴリ)。無ければ0
+  `parent_id` BIGINT NOT NULL DEFAULT 0,
   UNIQUE `uniq_tag_name` (`name`)
 ) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;
 
@@ -58,6 +61,7 @@ CREATE TABLE `livestream_tags` (
   `livestream_id` BIGINT NOT NULL,
   `tag_id` BIGINT NOT NULL
 ) ENGINE=InnoDB CHARACTER SET utf8mb4 COLLATE utf8mb4_bin;
+CREATE INDEX livestream_tags_tag_id ON livestream_tags(`tag_id`, `livestream_id`);
 
 -- ライブ配信視聴履歴
 CREATE TABLE `livestream_viewers_history` (
@@ -104,3 +104,10 @@ INSERT INTO tags(name) VALUES ('周年記念');
 INSERT INTO tags(name) VALUES ('サプライズ');
 INSERT INTO tags(name) VALUES ('椅子');
 
+
+-- タグのカテゴリ
+UPDATE tags SET parent_id = 2 WHERE id IN (6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16);
+UPDATE tags SET parent_id = 22 WHERE id IN (23, 24, 25, 26, 27, 28, 29, 30, 31, 96);
+UPDATE tags SET parent_id = 32 WHERE id IN (33, 34, 35, 36, 45);
+UPDATE tags SET parent_id = 38 WHERE id IN (39, 40);
+UPDATE tags SET parent_id = 76 WHERE id IN (77, 78, 79);
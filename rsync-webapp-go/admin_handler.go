@@ -0,0 +1,476 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	"github.com/miekg/dns"
+)
+
+const adminTokenEnvKey = "ISUCON13_ADMIN_TOKEN"
+
+type DNSReloadResponse struct {
+	Subdomains int `json:"subdomains"`
+}
+
+type DNSRecord struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	TTL  uint32 `json:"ttl"`
+	Data string `json:"data"`
+}
+
+type DNSZoneResponse struct {
+	Zone    string      `json:"zone"`
+	Serial  uint32      `json:"serial"`
+	Records []DNSRecord `json:"records"`
+}
+
+type DeleteDNSRecordsResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+type DNSQueryLogStatus struct {
+	Enabled bool `json:"enabled"`
+	// 出力形式(起動時の設定で決まり、切り替えでは変えられない)
+	Format string `json:"format,omitempty"`
+}
+
+// 管理用APIの認証
+// Authorization: Bearer <ISUCON13_ADMIN_TOKEN> が一致すること
+// 環境変数が未設定の場合は管理用APIそのものを無効にする
+func verifyAdmin(c echo.Context) error {
+	token, ok := os.LookupEnv(adminTokenEnvKey)
+	if !ok || token == "" {
+		return echo.NewHTTPError(http.StatusForbidden, "admin api is disabled")
+	}
+
+	given, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid admin token")
+	}
+	return nil
+}
+
+// DNSレコードファイルの再読み込みAPI
+// POST /api/admin/dns/reload
+func reloadDNSHandler(c echo.Context) error {
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	if err := reloadSubdomains(); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to reload dns records: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, &DNSReloadResponse{
+		Subdomains: countSubdomains(),
+	})
+}
+
+// DNSのRRLの統計API
+// GET /api/admin/dns/rrl
+func getDNSRRLStatsHandler(c echo.Context) error {
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dnsRRL.stats())
+}
+
+func toDNSRecord(rr dns.RR) DNSRecord {
+	return DNSRecord{
+		Name: rr.Header().Name,
+		Type: dns.TypeToString[rr.Header().Rrtype],
+		TTL:  rr.Header().Ttl,
+		Data: recordData(rr),
+	}
+}
+
+// DNSレコード一覧API
+// GET /api/admin/dns/records?name=pipe.t.isucon.pw.
+func getDNSRecordsHandler(c echo.Context) error {
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	name := c.QueryParam("name")
+	records := []DNSRecord{}
+	for _, rr := range zoneRecords() {
+		if name != "" && !strings.EqualFold(rr.Header().Name, dns.Fqdn(name)) {
+			continue
+		}
+		records = append(records, toDNSRecord(rr))
+	}
+	return c.JSON(http.StatusOK, records)
+}
+
+// DNSレコード追加API
+// POST /api/admin/dns/records
+// {"name": "www", "type": "CNAME", "ttl": 60, "data": "pipe.t.isucon.pw."}
+// nameは末尾にドットが無ければゾーンからの相対名とみなす
+func postDNSRecordHandler(c echo.Context) error {
+	defer c.Request().Body.Close()
+
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	var req DNSRecord
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "failed to decode the request body as json")
+	}
+	if req.Name == "" || req.Type == "" || req.Data == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "name, type and data are required")
+	}
+	if _, ok := dns.StringToType[strings.ToUpper(req.Type)]; !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, "unknown record type: "+req.Type)
+	}
+	name := req.Name
+	if !dns.IsFqdn(name) {
+		name = name + "." + dnsConf.Zone
+	}
+	if req.TTL == 0 {
+		req.TTL = dnsConf.AnswerTTL
+	}
+
+	rr, err := dns.NewRR(fmt.Sprintf("%s %d IN %s %s", name, req.TTL, strings.ToUpper(req.Type), req.Data))
+	if err != nil || rr == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid record: %v", err))
+	}
+	if err := addRecord(rr); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "failed to add record: "+err.Error())
+	}
+
+	return c.JSON(http.StatusCreated, toDNSRecord(rr))
+}
+
+// DNSレコード削除API
+// DELETE /api/admin/dns/records?name=www.t.isucon.pw.&type=CNAME&data=pipe.t.isucon.pw.
+// type, dataを省略した場合は、その名前の全てのレコードを削除する
+func deleteDNSRecordsHandler(c echo.Context) error {
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	name := c.QueryParam("name")
+	if name == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "name query parameter is required")
+	}
+	if !dns.IsFqdn(name) {
+		name = name + "." + dnsConf.Zone
+	}
+	var rrtype uint16
+	if t := c.QueryParam("type"); t != "" {
+		var ok bool
+		if rrtype, ok = dns.StringToType[strings.ToUpper(t)]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, "unknown record type: "+t)
+		}
+	}
+
+	deleted := deleteRecords(name, rrtype, c.QueryParam("data"))
+	if deleted == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "not found records that match the given name")
+	}
+	return c.JSON(http.StatusOK, &DeleteDNSRecordsResponse{Deleted: deleted})
+}
+
+// 応答中のゾーンの確認API
+// GET /api/admin/dns/zone?format=json|text
+// textの場合はゾーンファイル形式で返す
+func getDNSZoneHandler(c echo.Context) error {
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	records := zoneRecords()
+	switch c.QueryParam("format") {
+	case "", "json":
+		res := DNSZoneResponse{
+			Zone:    dnsConf.Zone,
+			Serial:  dnsSerial.Load(),
+			Records: make([]DNSRecord, len(records)),
+		}
+		for i, rr := range records {
+			res.Records[i] = toDNSRecord(rr)
+		}
+		return c.JSON(http.StatusOK, res)
+	case "text":
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "$ORIGIN %s\n", dnsConf.Zone)
+		for _, rr := range records {
+			sb.WriteString(rr.String())
+			sb.WriteString("\n")
+		}
+		return c.String(http.StatusOK, sb.String())
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "format query parameter must be json or text")
+	}
+}
+
+// DNSのクエリのメトリクスAPI
+// GET /api/admin/dns/metrics?top=10
+func getDNSMetricsHandler(c echo.Context) error {
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	top := 10
+	if v := c.QueryParam("top"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "top query parameter must be non-negative integer")
+		}
+		top = n
+	}
+	return c.JSON(http.StatusOK, dnsStats.snapshot(top))
+}
+
+// サブドメインで返すAPPサーバの状態確認API
+// GET /api/admin/dns/pool
+func getDNSAppPoolHandler(c echo.Context) error {
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, dnsAppPool.status())
+}
+
+// DNSのクエリログの状態確認API
+// GET /api/admin/dns/querylog
+func getDNSQueryLogHandler(c echo.Context) error {
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, &DNSQueryLogStatus{Enabled: dnsQueryLog.isEnabled(), Format: dnsQueryLog.format})
+}
+
+// DNSのクエリログの切り替えAPI
+// PUT /api/admin/dns/querylog
+// {"enabled": true}
+func putDNSQueryLogHandler(c echo.Context) error {
+	defer c.Request().Body.Close()
+
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	var req DNSQueryLogStatus
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "failed to decode the request body as json")
+	}
+	dnsQueryLog.setEnabled(req.Enabled)
+
+	return c.JSON(http.StatusOK, &DNSQueryLogStatus{Enabled: dnsQueryLog.isEnabled(), Format: dnsQueryLog.format})
+}
+
+// 管理用APIで返すタグ
+type AdminTag struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	ParentID int64  `json:"parent_id"`
+	Retired  bool   `json:"retired"`
+}
+
+type AdminTagsResponse struct {
+	Tags []*AdminTag `json:"tags"`
+}
+
+func toAdminTag(tag *Tag) *AdminTag {
+	return &AdminTag{ID: tag.ID, Name: tag.Name, ParentID: tag.ParentID, Retired: tag.Retired}
+}
+
+type TagRequest struct {
+	Name string `json:"name"`
+}
+
+type TagParentRequest struct {
+	// 0なら親を外す
+	ParentID int64 `json:"parent_id"`
+}
+
+type MergeTagsRequest struct {
+	// まとめ先のタグID
+	Into int64 `json:"into"`
+}
+
+const maxTagNameLength = 255
+
+func decodeTagRequest(c echo.Context) (string, error) {
+	var req TagRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "failed to decode the request body as json")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "name is required")
+	}
+	if utf8.RuneCountInString(name) > maxTagNameLength {
+		return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("name must be at most %d characters", maxTagNameLength))
+	}
+	return name, nil
+}
+
+func tagIDParam(c echo.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("tag_id"), 10, 64)
+	if err != nil {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "tag_id in path must be integer")
+	}
+	return id, nil
+}
+
+func tagRegistryError(err error) error {
+	switch {
+	case errors.Is(err, ErrTagNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "not found tag that has the given id")
+	case errors.Is(err, ErrTagAlreadyExists):
+		return echo.NewHTTPError(http.StatusConflict, "tag that has the given name already exists")
+	case errors.Is(err, ErrTagCycle):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to update tags: "+err.Error())
+	}
+}
+
+// タグ一覧API(使えなくしたタグも含む)
+// GET /api/admin/tags
+func getAdminTagsHandler(c echo.Context) error {
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	tags := tagRegistry.All()
+	res := &AdminTagsResponse{Tags: make([]*AdminTag, len(tags))}
+	for i, tag := range tags {
+		res.Tags[i] = toAdminTag(tag)
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// タグ追加API
+// POST /api/admin/tags
+// {"name": "新しいタグ"}
+func postAdminTagHandler(c echo.Context) error {
+	defer c.Request().Body.Close()
+
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	name, err := decodeTagRequest(c)
+	if err != nil {
+		return err
+	}
+	tag, err := tagRegistry.Create(c.Request().Context(), name)
+	if err != nil {
+		return tagRegistryError(err)
+	}
+	return c.JSON(http.StatusCreated, toAdminTag(tag))
+}
+
+// タグ名変更API
+// PUT /api/admin/tags/:tag_id
+// {"name": "新しい名前"}
+func putAdminTagHandler(c echo.Context) error {
+	defer c.Request().Body.Close()
+
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	id, err := tagIDParam(c)
+	if err != nil {
+		return err
+	}
+	name, err := decodeTagRequest(c)
+	if err != nil {
+		return err
+	}
+	tag, err := tagRegistry.Rename(c.Request().Context(), id, name)
+	if err != nil {
+		return tagRegistryError(err)
+	}
+	return c.JSON(http.StatusOK, toAdminTag(tag))
+}
+
+// タグの親(カテゴリ)変更API
+// PUT /api/admin/tags/:tag_id/parent
+// {"parent_id": 2}
+func putAdminTagParentHandler(c echo.Context) error {
+	defer c.Request().Body.Close()
+
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	id, err := tagIDParam(c)
+	if err != nil {
+		return err
+	}
+	var req TagParentRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "failed to decode the request body as json")
+	}
+	tag, err := tagRegistry.SetParent(c.Request().Context(), id, req.ParentID)
+	if err != nil {
+		return tagRegistryError(err)
+	}
+	return c.JSON(http.StatusOK, toAdminTag(tag))
+}
+
+// タグ統合API
+// POST /api/admin/tags/:tag_id/merge
+// {"into": 2}
+// :tag_idのタグが付いたライブ配信をintoのタグに付け替えて、:tag_idのタグを消す
+func mergeAdminTagHandler(c echo.Context) error {
+	defer c.Request().Body.Close()
+
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	id, err := tagIDParam(c)
+	if err != nil {
+		return err
+	}
+	var req MergeTagsRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "failed to decode the request body as json")
+	}
+	if req.Into == id {
+		return echo.NewHTTPError(http.StatusBadRequest, "cannot merge a tag into itself")
+	}
+	tag, err := tagRegistry.Merge(c.Request().Context(), id, req.Into)
+	if err != nil {
+		return tagRegistryError(err)
+	}
+	return c.JSON(http.StatusOK, toAdminTag(tag))
+}
+
+// タグ廃止API
+// POST /api/admin/tags/:tag_id/retire
+// 新しい予約には使えなくするが、既存のライブ配信には付いたまま残す
+func retireAdminTagHandler(c echo.Context) error {
+	if err := verifyAdmin(c); err != nil {
+		return err
+	}
+
+	id, err := tagIDParam(c)
+	if err != nil {
+		return err
+	}
+	tag, err := tagRegistry.Retire(c.Request().Context(), id)
+	if err != nil {
+		return tagRegistryError(err)
+	}
+	return c.JSON(http.StatusOK, toAdminTag(tag))
+}
@@ -1,81 +1,419 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"os"
 	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/miekg/dns"
 )
 
 var (
-	subdomains   = defaultSubdomains
-	muSubdomains = sync.RWMutex{}
+	// 名前解決できるサブドメインとそのレコード
+	// 水責め中も読み込みがロックで詰まらないように、スナップショットをatomicに差し替える(copy-on-write)
+	subdomains   atomic.Pointer[dnsZoneData]
+	muSubdomains = sync.Mutex{}
 	rrCache      = sync.Map{}
+
+	// レコードファイルから読み込んだ初期状態のサブドメイン一覧とレコード
+	// ファイルが指定されていない、もしくは読み込めなかった場合はdefaultSubdomainsを使う
+	baseSubdomains = defaultSubdomains()
+	baseRecords    []dns.RR
+	// addSubdomainで追加されたサブドメイン一覧(リロード時に引き継ぐ)
+	addedSubdomains []string
+	// 管理用APIで追加されたレコード(リロードやinitialize後も引き継ぐ)
+	adminRecords []dns.RR
 )
 
+func init() {
+	subdomains.Store(newZoneData([][]string{baseSubdomains}, nil))
+}
+
+// O(1)でサブドメインが登録済みか調べる
+// ロックを取らないので、DNSのホットパスから呼んでよい
+func hasSubdomain(name string) bool {
+	return lookupNode(name) != nil
+}
+
+func countSubdomains() int {
+	return len(*subdomains.Load())
+}
+
+// 現在のベースとユーザ追加分からゾーンを作り直す
+// muSubdomainsを取ってから呼ぶこと
+func rebuildSubdomains() {
+	records := make([]dns.RR, 0, len(baseRecords)+len(adminRecords))
+	records = append(records, baseRecords...)
+	records = append(records, adminRecords...)
+	subdomains.Store(newZoneData([][]string{baseSubdomains, addedSubdomains}, records))
+	// 差分を追えないので、IXFRはAXFRにフォールバックさせる
+	resetIXFRJournal()
+}
+
+// サブドメイン一覧を初期状態(レコードファイルの内容)に戻す
 func resetSubdomains() {
 	muSubdomains.Lock()
 	defer muSubdomains.Unlock()
-	subdomains = defaultSubdomains
+	addedSubdomains = nil
+	rebuildSubdomains()
 }
 
 func addSubdomain(subdomain string) {
 	muSubdomains.Lock()
 	defer muSubdomains.Unlock()
-	subdomains = append(subdomains, subdomain)
+
+	// 読み込み中のスナップショットは書き換えずに、コピーしてから追加する
+	current := *subdomains.Load()
+	if node, ok := current[strings.ToLower(subdomain)]; ok && node.app {
+		// 登録済みなら、作り直す時に何度も追加しないように一覧にも入れない
+		return
+	}
+	addedSubdomains = append(addedSubdomains, subdomain)
+	next := current.clone(1)
+	next.addName(subdomain)
+	subdomains.Store(&next)
+	recordIXFRDelta(nil, next[strings.ToLower(subdomain)].records(subdomain, dns.TypeA))
+}
+
+// 管理用APIからレコードを追加する
+// CNAMEと他のレコードは同じ名前に共存できない(RFC 1034)
+func addRecord(rr dns.RR) error {
+	name := dns.CanonicalName(rr.Header().Name)
+	rrtype := rr.Header().Rrtype
+	if !dns.IsSubDomain(dnsConf.Zone, name) {
+		return fmt.Errorf("%s is out of zone %s", name, dnsConf.Zone)
+	}
+	if name == dnsConf.Zone && (rrtype == dns.TypeSOA || rrtype == dns.TypeNS) {
+		return fmt.Errorf("%s of zone apex is managed by config", dns.TypeToString[rrtype])
+	}
+	if name == dnsConf.Zone && rrtype == dns.TypeCNAME {
+		return fmt.Errorf("CNAME is not allowed at zone apex")
+	}
+	rr.Header().Name = name
+
+	muSubdomains.Lock()
+	defer muSubdomains.Unlock()
+
+	current := *subdomains.Load()
+	if node, ok := current[name]; ok {
+		_, hasCNAME := node.rrsets[dns.TypeCNAME]
+		hasOther := node.app || len(node.rrsets) > 1 || (len(node.rrsets) == 1 && !hasCNAME)
+		if rrtype == dns.TypeCNAME && hasOther {
+			return fmt.Errorf("%s already has other records, CNAME cannot coexist", name)
+		}
+		if rrtype != dns.TypeCNAME && hasCNAME {
+			return fmt.Errorf("%s already has CNAME, other records cannot coexist", name)
+		}
+	}
+
+	adminRecords = append(adminRecords, rr)
+	next := current.clone(1)
+	next.addRecord(rr)
+	subdomains.Store(&next)
+	recordIXFRDelta(nil, []dns.RR{rr})
+	return nil
+}
+
+// 名前に一致するレコードを削除して、削除した件数を返す
+// rrtypeが0なら全ての型、rdataが空なら全ての値を対象にする
+// レコードファイル由来のものはリロードすると元に戻る
+func deleteRecords(name string, rrtype uint16, rdata string) int {
+	name = dns.CanonicalName(name)
+	match := func(rr dns.RR) bool {
+		if dns.CanonicalName(rr.Header().Name) != name {
+			return false
+		}
+		if rrtype != 0 && rr.Header().Rrtype != rrtype {
+			return false
+		}
+		return rdata == "" || recordData(rr) == rdata
+	}
+
+	muSubdomains.Lock()
+	defer muSubdomains.Unlock()
+
+	deleted := 0
+	for _, records := range []*[]dns.RR{&baseRecords, &adminRecords} {
+		n := len(*records)
+		*records = slices.DeleteFunc(slices.Clone(*records), match)
+		deleted += n - len(*records)
+	}
+	// APPサーバを指す名前はAレコードとして扱う
+	if (rrtype == 0 || rrtype == dns.TypeA) && (rdata == "" || dnsAppPool.contains(rdata)) {
+		for _, names := range []*[]string{&baseSubdomains, &addedSubdomains} {
+			n := len(*names)
+			*names = slices.DeleteFunc(slices.Clone(*names), func(s string) bool {
+				return dns.CanonicalName(s) == name
+			})
+			deleted += n - len(*names)
+		}
+	}
+	if deleted > 0 {
+		rebuildSubdomains()
+	}
+	return deleted
+}
+
+// 現在応答しているゾーンの全レコード
+// SOAを先頭に、名前順に並べる
+func zoneRecords() []dns.RR {
+	current := *subdomains.Load()
+	names := make([]string, 0, len(current))
+	for name := range current {
+		names = append(names, name)
+	}
+	// ゾーン頂点から近い順(ラベルを逆順にした辞書順)
+	slices.SortFunc(names, func(a, b string) int {
+		la, lb := dns.SplitDomainName(a), dns.SplitDomainName(b)
+		slices.Reverse(la)
+		slices.Reverse(lb)
+		return slices.Compare(la, lb)
+	})
+
+	records := []dns.RR{soaRR()}
+	for _, name := range names {
+		for _, rr := range current[name].allRecords(name) {
+			if rr.Header().Rrtype != dns.TypeSOA {
+				records = append(records, rr)
+			}
+		}
+	}
+	return records
+}
+
+// レコードの値の部分 (例: "192.168.0.12")
+func recordData(rr dns.RR) string {
+	return strings.TrimPrefix(rr.String(), rr.Header().String())
+}
+
+// usersテーブルを正として、登録済みユーザのサブドメインを作り直す
+// 再起動やinitialize後でも、登録済みユーザの名前解決ができるようにする
+func loadUserSubdomains(ctx context.Context) error {
+	var names []string
+	if err := dbConn.SelectContext(ctx, &names, "SELECT name FROM users"); err != nil {
+		return err
+	}
+	userSubdomains := make([]string, len(names))
+	for i, name := range names {
+		userSubdomains[i] = userSubdomain(name)
+	}
+
+	muSubdomains.Lock()
+	defer muSubdomains.Unlock()
+	addedSubdomains = userSubdomains
+	rebuildSubdomains()
+	return nil
+}
+
+// ユーザ名からサブドメイン(FQDN)を作る
+func userSubdomain(name string) string {
+	return name + "." + dnsConf.Zone
+}
+
+// レコードファイルを読み直して、初期状態のサブドメイン一覧とレコードを差し替える
+// レコードファイルが指定されていない場合は、設定のゾーンの既定のサブドメインにする
+// addSubdomainで追加されたものはそのまま残す
+func reloadSubdomains() error {
+	if dnsRecordsFile == "" {
+		setBaseSubdomains(defaultSubdomains(), nil)
+		return nil
+	}
+	names, records, err := loadRecordsFile(dnsRecordsFile)
+	if err != nil {
+		return err
+	}
+	setBaseSubdomains(names, records)
+	return nil
+}
+
+func setBaseSubdomains(names []string, records []dns.RR) {
+	muSubdomains.Lock()
+	defer muSubdomains.Unlock()
+	baseSubdomains = names
+	baseRecords = records
+	rebuildSubdomains()
 }
 
 // dns.RR はインターフェース
 // DNSリソースレコードの操作である、シリアライズ、デシリアライズ、比較などの機能を提供
 // 中のメソッドを実装するとこで、様々なタイプDNSレコードを（A,AAAA,MX,CNAMEなど)を統一的に扱うことが可能
-func newRR(s string) dns.RR {
+// パースに失敗したものはキャッシュしない
+func newRR(s string) (dns.RR, error) {
 	if rr, ok := rrCache.Load(s); ok {
-		return rr.(dns.RR)
+		return rr.(dns.RR), nil
+	}
+	r, err := dns.NewRR(s)
+	if err != nil {
+		return nil, err
+	}
+	if r == nil {
+		return nil, fmt.Errorf("empty resource record: %q", s)
 	}
-	r, _ := dns.NewRR(s)
 	rrCache.Store(s, r)
-	return r
+	return r, nil
+}
+
+// ゾーンのシリアル番号
+// 起動時刻を初期値にする
+var dnsSerial atomic.Uint32
+
+func init() {
+	dnsSerial.Store(uint32(time.Now().Unix()))
+}
+
+// ゾーンのSOAレコード
+// ネガティブキャッシュできるように、NXDOMAIN/NODATAの権威セクションにも入れる
+func soaRR() dns.RR {
+	ttl := dnsConf.NSTTL
+	// RFC 2308: ネガティブキャッシュのTTLはSOAのTTLとminimumの小さい方
+	if dnsConf.NegativeTTL < ttl {
+		ttl = dnsConf.NegativeTTL
+	}
+	return &dns.SOA{
+		Hdr:     dns.RR_Header{Name: dnsConf.Zone, Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: ttl},
+		Ns:      dnsConf.Nameservers[0].Name,
+		Mbox:    dnsConf.SOAMbox,
+		Serial:  dnsSerial.Load(),
+		Refresh: 3600,
+		Retry:   600,
+		Expire:  86400,
+		Minttl:  dnsConf.NegativeTTL,
+	}
+}
+
+// OPTレコードの長さの上限(これより大きいクエリはFORMERR)
+const maxOPTLen = 512
+
+// クエリとして受け付けられるか確認する
+// 受け付けられない場合は返すべきRcodeを返す
+func checkQuery(r *dns.Msg) (int, bool) {
+	if r.Opcode != dns.OpcodeQuery {
+		return dns.RcodeNotImplemented, false
+	}
+	if len(r.Question) != 1 {
+		return dns.RcodeFormatError, false
+	}
+	if c := r.Question[0].Qclass; c != dns.ClassINET && c != dns.ClassANY {
+		return dns.RcodeRefused, false
+	}
+
+	var opt *dns.OPT
+	for _, rr := range r.Extra {
+		o, ok := rr.(*dns.OPT)
+		if !ok {
+			continue
+		}
+		// OPTは1つだけで、名前はルート(RFC 6891)
+		if opt != nil || o.Hdr.Name != "." {
+			return dns.RcodeFormatError, false
+		}
+		opt = o
+	}
+	if opt != nil {
+		if dns.Len(opt) > maxOPTLen {
+			return dns.RcodeFormatError, false
+		}
+		if opt.Version() != 0 {
+			return dns.RcodeBadVers, false
+		}
+	}
+	return dns.RcodeSuccess, true
+}
+
+// 応答の書き込みに失敗した場合はログに残す
+func writeMsg(w dns.ResponseWriter, m *dns.Msg) error {
+	err := w.WriteMsg(m)
+	if err != nil {
+		log.Printf("failed to write dns response to %s: %+v", w.RemoteAddr(), err)
+	}
+	return err
 }
 
 // w: DNSレスポンスを書き込みするためのインターフェース
 // r: 受信したDNSクエリメッセージ
 func handle(w dns.ResponseWriter, r *dns.Msg) {
+	// 応答には応答しない(ループ対策)
+	if r.Response {
+		return
+	}
+
 	// レスポンスの準備
 	// 新しいDNSメッセージを作成し、受信したクエリに対する返信として設定
 	m := new(dns.Msg)
 	m.SetReply(r)
+	m.Authoritative = true
 
-	// "t.example.com." ドメインに対するNSクエリの場合、特定のNSレコードとAレコードを返す
-	// NSクエリの発行は、dig @*.*.*.* -p 50053 t.example.com NS +short
-	if r.Question[0].Qtype == dns.TypeNS && r.Question[0].Name == "t.isucon.pw." {
-		m.Answer = []dns.RR{
-			newRR("t.isucon.pw. 120 IN NS ns1.t.isucon.pw."),
-		}
-		m.Extra = []dns.RR{
-			newRR("ns1.t.isucon.pw. 120 IN A 192.168.0.11"),
+	if rcode, ok := checkQuery(r); !ok {
+		m.Authoritative = false
+		m.Rcode = rcode
+		if rcode == dns.RcodeBadVers {
+			// 拡張Rcodeを返すためにOPTを付ける
+			m.SetEdns0(dns.MinMsgSize, false)
 		}
-	} else {
-		muSubdomains.RLock()
-		defer muSubdomains.RUnlock()
+		writeMsg(w, m)
+		return
+	}
 
-		// subdomainsに含まれているならば、Aレコードを返す
-		if slices.Contains(subdomains, r.Question[0].Name) {
-			m.Answer = []dns.RR{
-				// 名前解決後のAPPサーバー
-				newRR(r.Question[0].Name + " 120 IN A 192.168.0.12"),
-			}
-		} else {
+	q := r.Question[0]
+	if q.Qtype == dns.TypeAXFR || q.Qtype == dns.TypeIXFR {
+		serveTransfer(w, r)
+		return
+	}
+	if !answerQuestion(m, q) {
+		switch dnsConf.NegativePolicy {
+		case dnsNegativeNXDomain:
+			m.Rcode = dns.RcodeNameError
+			m.Ns = []dns.RR{soaRR()}
+		case dnsNegativeRefused:
+			m.Rcode = dns.RcodeRefused
+			m.Authoritative = false
+		default:
 			// 水責めに対して対応=何も返さない
 			return
 		}
 	}
-	w.WriteMsg(m)
+	respond(w, r, m)
 }
 
 func startDNS() {
-	dns.HandleFunc("t.isucon.pw.", handle)
+	if path, ok := os.LookupEnv(dnsRecordsFileEnvKey); ok {
+		dnsRecordsFile = path
+	}
+	if err := reloadSubdomains(); err != nil {
+		log.Printf("failed to load DNS records file %s, fallback to default subdomains: %+v", dnsRecordsFile, err)
+		setBaseSubdomains(defaultSubdomains(), nil)
+	} else if dnsRecordsFile != "" {
+		log.Printf("loaded DNS records file %s", dnsRecordsFile)
+	}
+	go watchReloadSignal()
+
+	if len(dnsConf.DNSSECKeys) > 0 {
+		signer, err := loadDNSSECKeys(dnsConf.DNSSECKeys)
+		if err != nil {
+			log.Fatalf("failed to load DNSSEC keys: %+v", err)
+		}
+		dnsSigner = signer
+	}
+	dnsAppPool = newAppServerPool(dnsConf.AppServers)
+	if dnsConf.HealthCheckInterval > 0 {
+		go dnsAppPool.runHealthCheck(dnsConf.HealthCheckInterval, dnsConf.HealthCheckPort)
+	}
+	if dnsConf.RRL.ResponsesPerSecond > 0 {
+		dnsRRL = newResponseRateLimiter(dnsConf.RRL)
+	}
+	if l, err := openDNSQueryLogger(dnsConf.QueryLogFile, dnsConf.QueryLogFormat); err != nil {
+		log.Printf("failed to open DNS query log, fallback to stdout: %+v", err)
+	} else {
+		dnsQueryLog = l
+	}
+	dnsQueryLog.setEnabled(dnsConf.QueryLog)
+	dns.HandleFunc(dnsConf.Zone, withMetrics(withRRL(handle)))
 
 	fmt.Println("_________________________________________mydns")
 	fmt.Println(" __  __  __   __   ____   _   _   ____  ")
@@ -85,1289 +423,1325 @@ func startDNS() {
 	fmt.Println("|_|  |_|   |_|    |____/ |_| \\_| |____/ ")
 	fmt.Println("_________________________________________mydns")
 
-	srv := &dns.Server{Addr: ":53", Net: "udp"}
-	if err := srv.ListenAndServe(); err != nil {
+	// TCPフォールバックしてくるリゾルバのために、UDPとTCPの両方で待ち受ける
+	// どちらかが落ちたらもう片方も止めて、エラーを報告する
+	servers := []*dns.Server{
+		{Addr: ":53", Net: "udp"},
+		{Addr: ":53", Net: "tcp"},
+	}
+	errCh := make(chan error, len(servers))
+	for _, srv := range servers {
+		go func(srv *dns.Server) {
+			if err := srv.ListenAndServe(); err != nil {
+				errCh <- fmt.Errorf("%s: %w", srv.Net, err)
+				return
+			}
+			errCh <- nil
+		}(srv)
+	}
+
+	err := <-errCh
+	for _, srv := range servers {
+		srv.Shutdown()
+	}
+	if err != nil {
 		log.Fatal("Failed to start DNS server: ", err)
 	}
+	log.Fatal("DNS server stopped unexpectedly")
+}
+
+// 設定のゾーンでの既定のサブドメイン(FQDN)の一覧
+// レコードファイルが指定されていない、もしくは読み込めなかった場合に使う
+func defaultSubdomains() []string {
+	names := make([]string, len(defaultSubdomainLabels))
+	for i, label := range defaultSubdomainLabels {
+		if label == "" {
+			names[i] = dnsConf.Zone
+			continue
+		}
+		names[i] = label + "." + dnsConf.Zone
+	}
+	return names
 }
 
-var defaultSubdomains = []string{
-	"t.isucon.pw.",
-	"ns1.t.isucon.pw.",
-	"pipe.t.isucon.pw.",
-	"test001.t.isucon.pw.",
-	"www.t.isucon.pw.",
-	"www1.t.isucon.pw.",
-	"www2.t.isucon.pw.",
-	"www3.t.isucon.pw.",
-	"www4.t.isucon.pw.",
-	"www5.t.isucon.pw.",
-	"mail.t.isucon.pw.",
-	"acr-nema.t.isucon.pw.",
-	"afpovertcp.t.isucon.pw.",
-	"afs3-bos.t.isucon.pw.",
-	"afs3-callback.t.isucon.pw.",
-	"afs3-fileserver.t.isucon.pw.",
-	"afs3-kaserver.t.isucon.pw.",
-	"afs3-prserver.t.isucon.pw.",
-	"afs3-rmtsys.t.isucon.pw.",
-	"afs3-update.t.isucon.pw.",
-	"afs3-vlserver.t.isucon.pw.",
-	"afs3-volser.t.isucon.pw.",
-	"amanda.t.isucon.pw.",
-	"amandaidx.t.isucon.pw.",
-	"amidxtape.t.isucon.pw.",
-	"amqp.t.isucon.pw.",
-	"amqps.t.isucon.pw.",
-	"asf-rmcp.t.isucon.pw.",
-	"asp.t.isucon.pw.",
-	"auth.t.isucon.pw.",
-	"babel.t.isucon.pw.",
-	"bacula-dir.t.isucon.pw.",
-	"bacula-fd.t.isucon.pw.",
-	"bacula-sd.t.isucon.pw.",
-	"bbs.t.isucon.pw.",
-	"bgp.t.isucon.pw.",
-	"bgpd.t.isucon.pw.",
-	"biff.t.isucon.pw.",
-	"binkp.t.isucon.pw.",
-	"bootpc.t.isucon.pw.",
-	"bootps.t.isucon.pw.",
-	"canna.t.isucon.pw.",
-	"cfengine.t.isucon.pw.",
-	"chargen.t.isucon.pw.",
-	"cisco-sccp.t.isucon.pw.",
-	"clc-build-daemon.t.isucon.pw.",
-	"clearcase.t.isucon.pw.",
-	"cmip-agent.t.isucon.pw.",
-	"cmip-man.t.isucon.pw.",
-	"codaauth2.t.isucon.pw.",
-	"codasrv.t.isucon.pw.",
-	"codasrv-se.t.isucon.pw.",
-	"csync2.t.isucon.pw.",
-	"cvspserver.t.isucon.pw.",
-	"daap.t.isucon.pw.",
-	"datametrics.t.isucon.pw.",
-	"daytime.t.isucon.pw.",
-	"db-lsp.t.isucon.pw.",
-	"dcap.t.isucon.pw.",
-	"dhcpv6-client.t.isucon.pw.",
-	"dhcpv6-server.t.isucon.pw.",
-	"dicom.t.isucon.pw.",
-	"dict.t.isucon.pw.",
-	"dircproxy.t.isucon.pw.",
-	"discard.t.isucon.pw.",
-	"distcc.t.isucon.pw.",
-	"domain.t.isucon.pw.",
-	"domain-s.t.isucon.pw.",
-	"echo.t.isucon.pw.",
-	"epmap.t.isucon.pw.",
-	"epmd.t.isucon.pw.",
-	"exec.t.isucon.pw.",
-	"f5-globalsite.t.isucon.pw.",
-	"f5-iquery.t.isucon.pw.",
-	"fax.t.isucon.pw.",
-	"fido.t.isucon.pw.",
-	"finger.t.isucon.pw.",
-	"font-service.t.isucon.pw.",
-	"freeciv.t.isucon.pw.",
-	"fsp.t.isucon.pw.",
-	"ftp.t.isucon.pw.",
-	"ftp-data.t.isucon.pw.",
-	"ftps.t.isucon.pw.",
-	"ftps-data.t.isucon.pw.",
-	"gdomap.t.isucon.pw.",
-	"gds-db.t.isucon.pw.",
-	"git.t.isucon.pw.",
-	"gnunet.t.isucon.pw.",
-	"gnutella-rtr.t.isucon.pw.",
-	"gnutella-svc.t.isucon.pw.",
-	"gopher.t.isucon.pw.",
-	"gpsd.t.isucon.pw.",
-	"gris.t.isucon.pw.",
-	"groupwise.t.isucon.pw.",
-	"gsidcap.t.isucon.pw.",
-	"gsiftp.t.isucon.pw.",
-	"gsigatekeeper.t.isucon.pw.",
-	"hkp.t.isucon.pw.",
-	"http.t.isucon.pw.",
-	"http-alt.t.isucon.pw.",
-	"https.t.isucon.pw.",
-	"hylafax.t.isucon.pw.",
-	"iax.t.isucon.pw.",
-	"icpv2.t.isucon.pw.",
-	"imap2.t.isucon.pw.",
-	"imaps.t.isucon.pw.",
-	"ingreslock.t.isucon.pw.",
-	"ipp.t.isucon.pw.",
-	"iprop.t.isucon.pw.",
-	"ipsec-nat-t.t.isucon.pw.",
-	"ipx.t.isucon.pw.",
-	"ircd.t.isucon.pw.",
-	"ircs-u.t.isucon.pw.",
-	"isakmp.t.isucon.pw.",
-	"iscsi-target.t.isucon.pw.",
-	"isisd.t.isucon.pw.",
-	"isns.t.isucon.pw.",
-	"iso-tsap.t.isucon.pw.",
-	"kamandakerberos.t.isucon.pw.",
-	"kerberos-adm.t.isucon.pw.",
-	"kerberos-master.t.isucon.pw.",
-	"kerberos4.t.isucon.pw.",
-	"kermit.t.isucon.pw.",
-	"klogin.t.isucon.pw.",
-	"kpasswd.t.isucon.pw.",
-	"krb-prop.t.isucon.pw.",
-	"kshell.t.isucon.pw.",
-	"l2f.t.isucon.pw.",
-	"ldap.t.isucon.pw.",
-	"ldaps.t.isucon.pw.",
-	"ldp.t.isucon.pw.",
-	"login.t.isucon.pw.",
-	"lotusnote.t.isucon.pw.",
-	"mailq.t.isucon.pw.",
-	"mdns.t.isucon.pw.",
-	"microsoft-ds.t.isucon.pw.",
-	"moira-db.t.isucon.pw.",
-	"moira-update.t.isucon.pw.",
-	"moira-ureg.t.isucon.pw.",
-	"mon.t.isucon.pw.",
-	"ms-sql-m.t.isucon.pw.",
-	"ms-sql-s.t.isucon.pw.",
-	"ms-wbt-server.t.isucon.pw.",
-	"mtn.t.isucon.pw.",
-	"munin.t.isucon.pw.",
-	"mysql.t.isucon.pw.",
-	"mysql-proxy.t.isucon.pw.",
-	"nbd.t.isucon.pw.",
-	"nbp.t.isucon.pw.",
-	"netbios-dgm.t.isucon.pw.",
-	"netbios-ns.t.isucon.pw.",
-	"netbios-ssn.t.isucon.pw.",
-	"netstat.t.isucon.pw.",
-	"nfs.t.isucon.pw.",
-	"nntp.t.isucon.pw.",
-	"nntps.t.isucon.pw.",
-	"nqs.t.isucon.pw.",
-	"nrpe.t.isucon.pw.",
-	"nsca.t.isucon.pw.",
-	"ntalk.t.isucon.pw.",
-	"ntp.t.isucon.pw.",
-	"ntske.t.isucon.pw.",
-	"nut.t.isucon.pw.",
-	"omniorb.t.isucon.pw.",
-	"openvpn.t.isucon.pw.",
-	"ospf6d.t.isucon.pw.",
-	"ospfapi.t.isucon.pw.",
-	"ospfd.t.isucon.pw.",
-	"passwd-server.t.isucon.pw.",
-	"pawserv.t.isucon.pw.",
-	"pop3.t.isucon.pw.",
-	"pop3s.t.isucon.pw.",
-	"poppassd.t.isucon.pw.",
-	"postgresql.t.isucon.pw.",
-	"predict.t.isucon.pw.",
-	"printer.t.isucon.pw.",
-	"proofd.t.isucon.pw.",
-	"ptp-event.t.isucon.pw.",
-	"ptp-general.t.isucon.pw.",
-	"puppet.t.isucon.pw.",
-	"qmqp.t.isucon.pw.",
-	"qmtp.t.isucon.pw.",
-	"qotd.t.isucon.pw.",
-	"radius.t.isucon.pw.",
-	"radius-acct.t.isucon.pw.",
-	"radmin-port.t.isucon.pw.",
-	"redis.t.isucon.pw.",
-	"remctl.t.isucon.pw.",
-	"ripd.t.isucon.pw.",
-	"ripngd.t.isucon.pw.",
-	"rmiregistry.t.isucon.pw.",
-	"rmtcfg.t.isucon.pw.",
-	"rootd.t.isucon.pw.",
-	"route.t.isucon.pw.",
-	"rpc2portmap.t.isucon.pw.",
-	"rplay.t.isucon.pw.",
-	"rsync.t.isucon.pw.",
-	"rtcm-sc104.t.isucon.pw.",
-	"rtmp.t.isucon.pw.",
-	"rtsp.t.isucon.pw.",
-	"sa-msg-port.t.isucon.pw.",
-	"saft.t.isucon.pw.",
-	"sane-port.t.isucon.pw.",
-	"sge-execd.t.isucon.pw.",
-	"sge-qmaster.t.isucon.pw.",
-	"sgi-cad.t.isucon.pw.",
-	"sgi-cmsd.t.isucon.pw.",
-	"sgi-crsd.t.isucon.pw.",
-	"sgi-gcd.t.isucon.pw.",
-	"shell.t.isucon.pw.",
-	"sieve.t.isucon.pw.",
-	"silc.t.isucon.pw.",
-	"sip.t.isucon.pw.",
-	"sip-tls.t.isucon.pw.",
-	"skkserv.t.isucon.pw.",
-	"smtp.t.isucon.pw.",
-	"smux.t.isucon.pw.",
-	"snmp.t.isucon.pw.",
-	"snmp-trap.t.isucon.pw.",
-	"snpp.t.isucon.pw.",
-	"socks.t.isucon.pw.",
-	"spamd.t.isucon.pw.",
-	"ssh.t.isucon.pw.",
-	"submission.t.isucon.pw.",
-	"submissions.t.isucon.pw.",
-	"sunrpc.t.isucon.pw.",
-	"supfiledbg.t.isucon.pw.",
-	"supfilesrv.t.isucon.pw.",
-	"suucp.t.isucon.pw.",
-	"svn.t.isucon.pw.",
-	"svrloc.t.isucon.pw.",
-	"syslog.t.isucon.pw.",
-	"syslog-tls.t.isucon.pw.",
-	"sysrqd.t.isucon.pw.",
-	"systat.t.isucon.pw.",
-	"tacacs.t.isucon.pw.",
-	"talk.t.isucon.pw.",
-	"tcpmux.t.isucon.pw.",
-	"telnet.t.isucon.pw.",
-	"telnets.t.isucon.pw.",
-	"tfido.t.isucon.pw.",
-	"tftp.t.isucon.pw.",
-	"time.t.isucon.pw.",
-	"tinc.t.isucon.pw.",
-	"tproxy.t.isucon.pw.",
-	"uucp.t.isucon.pw.",
-	"venus.t.isucon.pw.",
-	"venus-se.t.isucon.pw.",
-	"webmin.t.isucon.pw.",
-	"who.t.isucon.pw.",
-	"whois.t.isucon.pw.",
-	"wnn6.t.isucon.pw.",
-	"x11.t.isucon.pw.",
-	"x11-1.t.isucon.pw.",
-	"x11-2.t.isucon.pw.",
-	"x11-3.t.isucon.pw.",
-	"x11-4.t.isucon.pw.",
-	"x11-5.t.isucon.pw.",
-	"x11-6.t.isucon.pw.",
-	"x11-7.t.isucon.pw.",
-	"xdmcp.t.isucon.pw.",
-	"xinetd.t.isucon.pw.",
-	"xmms2.t.isucon.pw.",
-	"xmpp-client.t.isucon.pw.",
-	"xmpp-server.t.isucon.pw.",
-	"xtel.t.isucon.pw.",
-	"xtelw.t.isucon.pw.",
-	"z3950.t.isucon.pw.",
-	"zabbix-agent.t.isucon.pw.",
-	"zabbix-trapper.t.isucon.pw.",
-	"zebra.t.isucon.pw.",
-	"zebrasrv.t.isucon.pw.",
-	"zephyr-clt.t.isucon.pw.",
-	"zephyr-hm.t.isucon.pw.",
-	"zephyr-srv.t.isucon.pw.",
-	"zip.t.isucon.pw.",
-	"zope.t.isucon.pw.",
-	"zope-ftp.t.isucon.pw.",
-	"zserv.t.isucon.pw.",
-	"ayamazaki0.t.isucon.pw.",
-	"yoshidamiki0.t.isucon.pw.",
-	"hidekimurakami0.t.isucon.pw.",
-	"akemikobayashi0.t.isucon.pw.",
-	"eishikawa0.t.isucon.pw.",
-	"tomoya100.t.isucon.pw.",
-	"kobayashiminoru0.t.isucon.pw.",
-	"yamadakaori0.t.isucon.pw.",
-	"tanakahanako0.t.isucon.pw.",
-	"taro660.t.isucon.pw.",
-	"hashimotokenichi0.t.isucon.pw.",
-	"yuta330.t.isucon.pw.",
-	"kenichinakamura0.t.isucon.pw.",
-	"taichikato0.t.isucon.pw.",
-	"wfujita0.t.isucon.pw.",
-	"saitotakuma0.t.isucon.pw.",
-	"maayafujiwara0.t.isucon.pw.",
-	"akira680.t.isucon.pw.",
-	"vmaeda0.t.isucon.pw.",
-	"jnakamura0.t.isucon.pw.",
-	"suzukitsubasa0.t.isucon.pw.",
-	"yoshidatomoya0.t.isucon.pw.",
-	"qendo0.t.isucon.pw.",
-	"haruka030.t.isucon.pw.",
-	"saitotakuma1.t.isucon.pw.",
-	"bsuzuki0.t.isucon.pw.",
-	"shohei720.t.isucon.pw.",
-	"naoko980.t.isucon.pw.",
-	"suzukiryohei0.t.isucon.pw.",
-	"kobayashisayuri0.t.isucon.pw.",
-	"ykobayashi0.t.isucon.pw.",
-	"asuzuki0.t.isucon.pw.",
-	"sotaro880.t.isucon.pw.",
-	"nyamaguchi0.t.isucon.pw.",
-	"momokohashimoto0.t.isucon.pw.",
-	"suzukinaoki0.t.isucon.pw.",
-	"wgoto0.t.isucon.pw.",
-	"tomoya540.t.isucon.pw.",
-	"fujitayoichi0.t.isucon.pw.",
-	"kaorikato0.t.isucon.pw.",
-	"chiyo810.t.isucon.pw.",
-	"yito0.t.isucon.pw.",
-	"tomoyakato0.t.isucon.pw.",
-	"ryosukeabe0.t.isucon.pw.",
-	"smatsumoto0.t.isucon.pw.",
-	"vwatanabe0.t.isucon.pw.",
-	"sayuri650.t.isucon.pw.",
-	"takahashinaoto0.t.isucon.pw.",
-	"hiroshisuzuki0.t.isucon.pw.",
-	"xyamamoto0.t.isucon.pw.",
-	"osaito0.t.isucon.pw.",
-	"esato0.t.isucon.pw.",
-	"oshimizu0.t.isucon.pw.",
-	"yamadamituru0.t.isucon.pw.",
-	"wmori0.t.isucon.pw.",
-	"saitorei0.t.isucon.pw.",
-	"kimuramiki0.t.isucon.pw.",
-	"sasakiyosuke0.t.isucon.pw.",
-	"kumiko410.t.isucon.pw.",
-	"qkobayashi0.t.isucon.pw.",
-	"akondo0.t.isucon.pw.",
-	"ywatanabe0.t.isucon.pw.",
-	"otanaoki0.t.isucon.pw.",
-	"naotosasaki0.t.isucon.pw.",
-	"momokosuzuki0.t.isucon.pw.",
-	"maayayoshida0.t.isucon.pw.",
-	"hashimotoasuka0.t.isucon.pw.",
-	"maayanakagawa0.t.isucon.pw.",
-	"ltanaka0.t.isucon.pw.",
-	"jyamada0.t.isucon.pw.",
-	"yasuhiro650.t.isucon.pw.",
-	"hiroshitanaka0.t.isucon.pw.",
-	"yamashitaakemi0.t.isucon.pw.",
-	"hidekiishikawa0.t.isucon.pw.",
-	"kanatakahashi0.t.isucon.pw.",
-	"akemiito0.t.isucon.pw.",
-	"manabu800.t.isucon.pw.",
-	"shota790.t.isucon.pw.",
-	"naokoaoki0.t.isucon.pw.",
-	"gyamamoto0.t.isucon.pw.",
-	"xyamaguchi0.t.isucon.pw.",
-	"katokumiko0.t.isucon.pw.",
-	"xyamada0.t.isucon.pw.",
-	"qyamashita0.t.isucon.pw.",
-	"pwatanabe0.t.isucon.pw.",
-	"ryoheiishikawa0.t.isucon.pw.",
-	"nakamuraharuka0.t.isucon.pw.",
-	"pfukuda0.t.isucon.pw.",
-	"saitoyosuke0.t.isucon.pw.",
-	"morijun0.t.isucon.pw.",
-	"hiroshiokamoto0.t.isucon.pw.",
-	"kazuyahayashi0.t.isucon.pw.",
-	"anakajima0.t.isucon.pw.",
-	"maaya110.t.isucon.pw.",
-	"kazuya250.t.isucon.pw.",
-	"kondoakira0.t.isucon.pw.",
-	"atsushi920.t.isucon.pw.",
-	"hanakokondo0.t.isucon.pw.",
-	"naoki220.t.isucon.pw.",
-	"hiroshi180.t.isucon.pw.",
-	"jun820.t.isucon.pw.",
-	"onakamura0.t.isucon.pw.",
-	"takuma040.t.isucon.pw.",
-	"kobayashiyasuhiro0.t.isucon.pw.",
-	"reiwatanabe0.t.isucon.pw.",
-	"hideki630.t.isucon.pw.",
-	"yutayoshida0.t.isucon.pw.",
-	"yutamori0.t.isucon.pw.",
-	"ryosukeyamamoto0.t.isucon.pw.",
-	"manabuota0.t.isucon.pw.",
-	"nsakamoto0.t.isucon.pw.",
-	"wtanaka0.t.isucon.pw.",
-	"inakamura0.t.isucon.pw.",
-	"ymori0.t.isucon.pw.",
-	"nfujii0.t.isucon.pw.",
-	"osamu950.t.isucon.pw.",
-	"yuinakagawa0.t.isucon.pw.",
-	"uhayashi0.t.isucon.pw.",
-	"momoko070.t.isucon.pw.",
-	"myamada0.t.isucon.pw.",
-	"zyoshida0.t.isucon.pw.",
-	"watanabesatomi0.t.isucon.pw.",
-	"watanabemanabu0.t.isucon.pw.",
-	"momokosuzuki1.t.isucon.pw.",
-	"jokada0.t.isucon.pw.",
-	"hanako640.t.isucon.pw.",
-	"rmatsumoto0.t.isucon.pw.",
-	"maayasasaki0.t.isucon.pw.",
-	"ryohei450.t.isucon.pw.",
-	"takuma570.t.isucon.pw.",
-	"yokotanaka0.t.isucon.pw.",
-	"mai270.t.isucon.pw.",
-	"rikasakamoto0.t.isucon.pw.",
-	"taro150.t.isucon.pw.",
-	"suzukimomoko0.t.isucon.pw.",
-	"watanabeyoichi0.t.isucon.pw.",
-	"yuki620.t.isucon.pw.",
-	"tsubasainoue0.t.isucon.pw.",
-	"yokotakahashi0.t.isucon.pw.",
-	"yumikohayashi0.t.isucon.pw.",
-	"yamaguchiyumiko0.t.isucon.pw.",
-	"kkato0.t.isucon.pw.",
-	"minoruyoshida0.t.isucon.pw.",
-	"tmurakami0.t.isucon.pw.",
-	"hasegawanaoto0.t.isucon.pw.",
-	"kumikowatanabe0.t.isucon.pw.",
-	"lota0.t.isucon.pw.",
-	"yoichi440.t.isucon.pw.",
-	"sayurikondo0.t.isucon.pw.",
-	"xogawa0.t.isucon.pw.",
-	"naoki250.t.isucon.pw.",
-	"eokada0.t.isucon.pw.",
-	"satomiyamamoto0.t.isucon.pw.",
-	"asukamaeda0.t.isucon.pw.",
-	"momokowatanabe0.t.isucon.pw.",
-	"nanamisuzuki0.t.isucon.pw.",
-	"gotomanabu0.t.isucon.pw.",
-	"maiyamazaki0.t.isucon.pw.",
-	"junito0.t.isucon.pw.",
-	"aokikazuya0.t.isucon.pw.",
-	"shohei040.t.isucon.pw.",
-	"momokotanaka0.t.isucon.pw.",
-	"atsushimatsumoto0.t.isucon.pw.",
-	"taichifukuda0.t.isucon.pw.",
-	"haruka630.t.isucon.pw.",
-	"tanakashohei0.t.isucon.pw.",
-	"qnishimura0.t.isucon.pw.",
-	"mkondo0.t.isucon.pw.",
-	"kimurayui0.t.isucon.pw.",
-	"tomoyanakajima0.t.isucon.pw.",
-	"naoko310.t.isucon.pw.",
-	"yukiokada0.t.isucon.pw.",
-	"tsubasasuzuki0.t.isucon.pw.",
-	"yutatakahashi0.t.isucon.pw.",
-	"kyosuke140.t.isucon.pw.",
-	"tomoya190.t.isucon.pw.",
-	"ryohei610.t.isucon.pw.",
-	"fukudahiroshi0.t.isucon.pw.",
-	"tyamaguchi0.t.isucon.pw.",
-	"rsasaki0.t.isucon.pw.",
-	"satoyoichi0.t.isucon.pw.",
-	"rikawatanabe0.t.isucon.pw.",
-	"vokada0.t.isucon.pw.",
-	"mituru070.t.isucon.pw.",
-	"nakajimayui0.t.isucon.pw.",
-	"nanamiota0.t.isucon.pw.",
-	"smatsuda0.t.isucon.pw.",
-	"suzukijun0.t.isucon.pw.",
-	"saitosayuri0.t.isucon.pw.",
-	"haruka730.t.isucon.pw.",
-	"yamamotoakemi0.t.isucon.pw.",
-	"satoyosuke0.t.isucon.pw.",
-	"maayasato0.t.isucon.pw.",
-	"nanami830.t.isucon.pw.",
-	"kanaokamoto0.t.isucon.pw.",
-	"usuzuki0.t.isucon.pw.",
-	"manabusasaki0.t.isucon.pw.",
-	"kenichi870.t.isucon.pw.",
-	"tomoyasato0.t.isucon.pw.",
-	"mituruhayashi0.t.isucon.pw.",
-	"junnishimura0.t.isucon.pw.",
-	"fujiwaramituru0.t.isucon.pw.",
-	"yoshidamai0.t.isucon.pw.",
-	"yosuke350.t.isucon.pw.",
-	"xwatanabe0.t.isucon.pw.",
-	"naokimatsumoto0.t.isucon.pw.",
-	"kenichi170.t.isucon.pw.",
-	"naoko340.t.isucon.pw.",
-	"nakajimasotaro0.t.isucon.pw.",
-	"eito0.t.isucon.pw.",
-	"kenichi470.t.isucon.pw.",
-	"qabe0.t.isucon.pw.",
-	"junkondo0.t.isucon.pw.",
-	"yosukewatanabe0.t.isucon.pw.",
-	"ryosuke040.t.isucon.pw.",
-	"itosotaro0.t.isucon.pw.",
-	"kimuramikako0.t.isucon.pw.",
-	"reiogawa0.t.isucon.pw.",
-	"myamada1.t.isucon.pw.",
-	"yamazakisayuri0.t.isucon.pw.",
-	"gotoakemi0.t.isucon.pw.",
-	"shohei660.t.isucon.pw.",
-	"phasegawa0.t.isucon.pw.",
-	"kana990.t.isucon.pw.",
-	"yosuke710.t.isucon.pw.",
-	"rika420.t.isucon.pw.",
-	"ikedajun0.t.isucon.pw.",
-	"takuma250.t.isucon.pw.",
-	"yoichihayashi0.t.isucon.pw.",
-	"yoichi441.t.isucon.pw.",
-	"zmiura0.t.isucon.pw.",
-	"ftakahashi0.t.isucon.pw.",
-	"oinoue0.t.isucon.pw.",
-	"osamu920.t.isucon.pw.",
-	"takahashikumiko0.t.isucon.pw.",
-	"tsubasamaeda0.t.isucon.pw.",
-	"ryosuke220.t.isucon.pw.",
-	"yoichimurakami0.t.isucon.pw.",
-	"abeyumiko0.t.isucon.pw.",
-	"tishii0.t.isucon.pw.",
-	"vkato0.t.isucon.pw.",
-	"mituru710.t.isucon.pw.",
-	"asukamurakami0.t.isucon.pw.",
-	"yuiwatanabe0.t.isucon.pw.",
-	"jokamoto0.t.isucon.pw.",
-	"akirafujiwara0.t.isucon.pw.",
-	"lgoto0.t.isucon.pw.",
-	"akiratakahashi0.t.isucon.pw.",
-	"lhashimoto0.t.isucon.pw.",
-	"ogawarika0.t.isucon.pw.",
-	"manabu620.t.isucon.pw.",
-	"osamuyamaguchi0.t.isucon.pw.",
-	"fujiwararei0.t.isucon.pw.",
-	"yosukewatanabe1.t.isucon.pw.",
-	"asuka500.t.isucon.pw.",
-	"otamaaya0.t.isucon.pw.",
-	"okamotonaoko0.t.isucon.pw.",
-	"tokamoto0.t.isucon.pw.",
-	"saitomituru0.t.isucon.pw.",
-	"suzukitakuma0.t.isucon.pw.",
-	"suzukimaaya0.t.isucon.pw.",
-	"nanamitanaka0.t.isucon.pw.",
-	"suzukikana0.t.isucon.pw.",
-	"sakamotoshohei0.t.isucon.pw.",
-	"kanayamamoto0.t.isucon.pw.",
-	"xtakahashi0.t.isucon.pw.",
-	"wsuzuki0.t.isucon.pw.",
-	"taichi990.t.isucon.pw.",
-	"saitoyuki0.t.isucon.pw.",
-	"rei050.t.isucon.pw.",
-	"nmaeda0.t.isucon.pw.",
-	"csuzuki0.t.isucon.pw.",
-	"takahashiatsushi0.t.isucon.pw.",
-	"yukifukuda0.t.isucon.pw.",
-	"yumiko680.t.isucon.pw.",
-	"yamadayoko0.t.isucon.pw.",
-	"abeosamu0.t.isucon.pw.",
-	"taro210.t.isucon.pw.",
-	"katomai0.t.isucon.pw.",
-	"hasegawakumiko0.t.isucon.pw.",
-	"vhashimoto0.t.isucon.pw.",
-	"tanakamai0.t.isucon.pw.",
-	"hmori0.t.isucon.pw.",
-	"naokoyamaguchi0.t.isucon.pw.",
-	"yamaguchiyuta0.t.isucon.pw.",
-	"iyamamoto0.t.isucon.pw.",
-	"yutanakajima0.t.isucon.pw.",
-	"kyosukesasaki0.t.isucon.pw.",
-	"satosatomi0.t.isucon.pw.",
-	"kkato1.t.isucon.pw.",
-	"kumiko980.t.isucon.pw.",
-	"jishii0.t.isucon.pw.",
-	"yutafujiwara0.t.isucon.pw.",
-	"fukudamomoko0.t.isucon.pw.",
-	"kumikokobayashi0.t.isucon.pw.",
-	"naoki350.t.isucon.pw.",
-	"sayuri230.t.isucon.pw.",
-	"morijun1.t.isucon.pw.",
-	"mituru840.t.isucon.pw.",
-	"eyoshida0.t.isucon.pw.",
-	"taichi460.t.isucon.pw.",
-	"esasaki0.t.isucon.pw.",
-	"yumikonakamura0.t.isucon.pw.",
-	"akemi540.t.isucon.pw.",
-	"kazuya770.t.isucon.pw.",
-	"suzukiharuka0.t.isucon.pw.",
-	"harukagoto0.t.isucon.pw.",
-	"vkato1.t.isucon.pw.",
-	"bmatsumoto0.t.isucon.pw.",
-	"yamazakikaori0.t.isucon.pw.",
-	"yamashitasatomi0.t.isucon.pw.",
-	"fujiimikako0.t.isucon.pw.",
-	"ekato0.t.isucon.pw.",
-	"asukaito0.t.isucon.pw.",
-	"aokimiki0.t.isucon.pw.",
-	"katotaichi0.t.isucon.pw.",
-	"harukasasaki0.t.isucon.pw.",
-	"manabuyamamoto0.t.isucon.pw.",
-	"etanaka0.t.isucon.pw.",
-	"nakagawamituru0.t.isucon.pw.",
-	"hiroshikobayashi0.t.isucon.pw.",
-	"satomi900.t.isucon.pw.",
-	"akira180.t.isucon.pw.",
-	"manabu710.t.isucon.pw.",
-	"minoru160.t.isucon.pw.",
-	"fujiwarataro0.t.isucon.pw.",
-	"tomoya240.t.isucon.pw.",
-	"taichinakajima0.t.isucon.pw.",
-	"atsushitakahashi0.t.isucon.pw.",
-	"yoichi150.t.isucon.pw.",
-	"fito0.t.isucon.pw.",
-	"satomitakahashi0.t.isucon.pw.",
-	"akemi230.t.isucon.pw.",
-	"akato0.t.isucon.pw.",
-	"asuka250.t.isucon.pw.",
-	"atsushiinoue0.t.isucon.pw.",
-	"sasakirei0.t.isucon.pw.",
-	"yamadamiki0.t.isucon.pw.",
-	"taro890.t.isucon.pw.",
-	"yamamotohiroshi0.t.isucon.pw.",
-	"vtakahashi0.t.isucon.pw.",
-	"yoichiyoshida0.t.isucon.pw.",
-	"nishimurashohei0.t.isucon.pw.",
-	"zsasaki0.t.isucon.pw.",
-	"shimizumaaya0.t.isucon.pw.",
-	"inoueosamu0.t.isucon.pw.",
-	"hsato0.t.isucon.pw.",
-	"kimuraharuka0.t.isucon.pw.",
-	"takumayoshida0.t.isucon.pw.",
-	"ikedahideki0.t.isucon.pw.",
-	"kobayashitsubasa0.t.isucon.pw.",
-	"jmurakami0.t.isucon.pw.",
-	"kaori320.t.isucon.pw.",
-	"suzukimiki0.t.isucon.pw.",
-	"gtanaka0.t.isucon.pw.",
-	"shota220.t.isucon.pw.",
-	"fyamada0.t.isucon.pw.",
-	"nishimuraminoru0.t.isucon.pw.",
-	"ttanaka0.t.isucon.pw.",
-	"satomi280.t.isucon.pw.",
-	"maaya390.t.isucon.pw.",
-	"snakamura0.t.isucon.pw.",
-	"okamototsubasa0.t.isucon.pw.",
-	"etakahashi0.t.isucon.pw.",
-	"taro500.t.isucon.pw.",
-	"yasuhirotakahashi0.t.isucon.pw.",
-	"uhasegawa0.t.isucon.pw.",
-	"miki470.t.isucon.pw.",
-	"ryosukeito0.t.isucon.pw.",
-	"kanasaito0.t.isucon.pw.",
-	"dyamashita0.t.isucon.pw.",
-	"taichi180.t.isucon.pw.",
-	"iyamazaki0.t.isucon.pw.",
-	"yui950.t.isucon.pw.",
-	"kumikomaeda0.t.isucon.pw.",
-	"yuiwatanabe1.t.isucon.pw.",
-	"suzukishota0.t.isucon.pw.",
-	"vsuzuki0.t.isucon.pw.",
-	"tanakahiroshi0.t.isucon.pw.",
-	"yosukekimura0.t.isucon.pw.",
-	"saitoryosuke0.t.isucon.pw.",
-	"ymori1.t.isucon.pw.",
-	"xnakajima0.t.isucon.pw.",
-	"zyamazaki0.t.isucon.pw.",
-	"mikakoyamamoto0.t.isucon.pw.",
-	"tanakahideki0.t.isucon.pw.",
-	"ekato1.t.isucon.pw.",
-	"momokomori0.t.isucon.pw.",
-	"tsubasa260.t.isucon.pw.",
-	"mikako290.t.isucon.pw.",
-	"yoko160.t.isucon.pw.",
-	"yuki480.t.isucon.pw.",
-	"hasegawakumiko1.t.isucon.pw.",
-	"itokana0.t.isucon.pw.",
-	"fkobayashi0.t.isucon.pw.",
-	"tomoya630.t.isucon.pw.",
-	"yuki730.t.isucon.pw.",
-	"vyamaguchi0.t.isucon.pw.",
-	"tshimizu0.t.isucon.pw.",
-	"pota0.t.isucon.pw.",
-	"hiroshi960.t.isucon.pw.",
-	"katoyui0.t.isucon.pw.",
-	"mkondo1.t.isucon.pw.",
-	"rika500.t.isucon.pw.",
-	"einoue0.t.isucon.pw.",
-	"yamashitayumiko0.t.isucon.pw.",
-	"kazuya680.t.isucon.pw.",
-	"asukakobayashi0.t.isucon.pw.",
-	"miki320.t.isucon.pw.",
-	"yumikosuzuki0.t.isucon.pw.",
-	"naotoito0.t.isucon.pw.",
-	"esuzuki0.t.isucon.pw.",
-	"hiroshiaoki0.t.isucon.pw.",
-	"vishikawa0.t.isucon.pw.",
-	"akiraito0.t.isucon.pw.",
-	"mikako020.t.isucon.pw.",
-	"miurasatomi0.t.isucon.pw.",
-	"etakahashi1.t.isucon.pw.",
-	"tarosato0.t.isucon.pw.",
-	"matsumotomanabu0.t.isucon.pw.",
-	"naoko560.t.isucon.pw.",
-	"kaori570.t.isucon.pw.",
-	"minoru210.t.isucon.pw.",
-	"jsuzuki0.t.isucon.pw.",
-	"tanakashohei1.t.isucon.pw.",
-	"tyamada0.t.isucon.pw.",
-	"kenichi160.t.isucon.pw.",
-	"shotayamada0.t.isucon.pw.",
-	"hiroshi070.t.isucon.pw.",
-	"hidekisakamoto0.t.isucon.pw.",
-	"naokiikeda0.t.isucon.pw.",
-	"nakagawamaaya0.t.isucon.pw.",
-	"hiroshitanaka1.t.isucon.pw.",
-	"yamashitamaaya0.t.isucon.pw.",
-	"momokoabe0.t.isucon.pw.",
-	"vito0.t.isucon.pw.",
-	"sakamotomikako0.t.isucon.pw.",
-	"asaito0.t.isucon.pw.",
-	"ryosukewatanabe0.t.isucon.pw.",
-	"nanami140.t.isucon.pw.",
-	"tanakahanako1.t.isucon.pw.",
-	"rei390.t.isucon.pw.",
-	"rito0.t.isucon.pw.",
-	"akemiinoue0.t.isucon.pw.",
-	"jendo0.t.isucon.pw.",
-	"cwatanabe0.t.isucon.pw.",
-	"rikakobayashi0.t.isucon.pw.",
-	"kaoriokamoto0.t.isucon.pw.",
-	"momoko350.t.isucon.pw.",
-	"sayuri130.t.isucon.pw.",
-	"kaoritanaka0.t.isucon.pw.",
-	"mikakokobayashi0.t.isucon.pw.",
-	"naoto500.t.isucon.pw.",
-	"suzukinaoto0.t.isucon.pw.",
-	"myamamoto0.t.isucon.pw.",
-	"yuta090.t.isucon.pw.",
-	"asato0.t.isucon.pw.",
-	"chiyo580.t.isucon.pw.",
-	"lmatsumoto0.t.isucon.pw.",
-	"kaori190.t.isucon.pw.",
-	"mai860.t.isucon.pw.",
-	"naokokimura0.t.isucon.pw.",
-	"tomoya400.t.isucon.pw.",
-	"dota0.t.isucon.pw.",
-	"osamu590.t.isucon.pw.",
-	"takahashitomoya0.t.isucon.pw.",
-	"shimizuakemi0.t.isucon.pw.",
-	"endokenichi0.t.isucon.pw.",
-	"yoshidaryohei0.t.isucon.pw.",
-	"ryosukemurakami0.t.isucon.pw.",
-	"reitanaka0.t.isucon.pw.",
-	"akemi910.t.isucon.pw.",
-	"enakamura0.t.isucon.pw.",
-	"ohayashi0.t.isucon.pw.",
-	"takumasuzuki0.t.isucon.pw.",
-	"suzukijun1.t.isucon.pw.",
-	"bsaito0.t.isucon.pw.",
-	"maifujiwara0.t.isucon.pw.",
-	"ikedayoko0.t.isucon.pw.",
-	"kimurakenichi0.t.isucon.pw.",
-	"kyosuke210.t.isucon.pw.",
-	"shota870.t.isucon.pw.",
-	"fyamaguchi0.t.isucon.pw.",
-	"miki730.t.isucon.pw.",
-	"sayurihashimoto0.t.isucon.pw.",
-	"naoki460.t.isucon.pw.",
-	"satoyumiko0.t.isucon.pw.",
-	"minoruhashimoto0.t.isucon.pw.",
-	"nanamimurakami0.t.isucon.pw.",
-	"mai600.t.isucon.pw.",
-	"taro340.t.isucon.pw.",
-	"yosuke950.t.isucon.pw.",
-	"rokada0.t.isucon.pw.",
-	"nanami710.t.isucon.pw.",
-	"zishikawa0.t.isucon.pw.",
-	"yutawatanabe0.t.isucon.pw.",
-	"suzukiosamu0.t.isucon.pw.",
-	"mituru130.t.isucon.pw.",
-	"taichi000.t.isucon.pw.",
-	"chiyoyamamoto0.t.isucon.pw.",
-	"yoshidakyosuke0.t.isucon.pw.",
-	"vyamazaki0.t.isucon.pw.",
-	"wwatanabe0.t.isucon.pw.",
-	"ykimura0.t.isucon.pw.",
-	"tanakahideki1.t.isucon.pw.",
-	"minoruito0.t.isucon.pw.",
-	"shotamatsumoto0.t.isucon.pw.",
-	"watanabehiroshi0.t.isucon.pw.",
-	"taichi560.t.isucon.pw.",
-	"nanami090.t.isucon.pw.",
-	"akira800.t.isucon.pw.",
-	"suzukiyasuhiro0.t.isucon.pw.",
-	"momokofukuda0.t.isucon.pw.",
-	"kana350.t.isucon.pw.",
-	"tanakanaoto0.t.isucon.pw.",
-	"akemi800.t.isucon.pw.",
-	"tanakakenichi0.t.isucon.pw.",
-	"oyoshida0.t.isucon.pw.",
-	"yutamatsumoto0.t.isucon.pw.",
-	"hiroshisuzuki1.t.isucon.pw.",
-	"yamaguchikazuya0.t.isucon.pw.",
-	"yoko800.t.isucon.pw.",
-	"lnakamura0.t.isucon.pw.",
-	"esato1.t.isucon.pw.",
-	"atsushitakahashi1.t.isucon.pw.",
-	"eshimizu0.t.isucon.pw.",
-	"reitanaka1.t.isucon.pw.",
-	"yuki500.t.isucon.pw.",
-	"tanakashohei2.t.isucon.pw.",
-	"akiranishimura0.t.isucon.pw.",
-	"naokiyamamoto0.t.isucon.pw.",
-	"shimizuatsushi0.t.isucon.pw.",
-	"winoue0.t.isucon.pw.",
-	"hiroshifujita0.t.isucon.pw.",
-	"mikako380.t.isucon.pw.",
-	"gotoshohei0.t.isucon.pw.",
-	"yumikoyamamoto0.t.isucon.pw.",
-	"gmatsuda0.t.isucon.pw.",
-	"kumikokobayashi1.t.isucon.pw.",
-	"tsubasatakahashi0.t.isucon.pw.",
-	"yyamamoto0.t.isucon.pw.",
-	"nakamurajun0.t.isucon.pw.",
-	"yasuhirokato0.t.isucon.pw.",
-	"vsuzuki1.t.isucon.pw.",
-	"kobayashihideki0.t.isucon.pw.",
-	"murakamiosamu0.t.isucon.pw.",
-	"mikako340.t.isucon.pw.",
-	"takumaokada0.t.isucon.pw.",
-	"osamusato0.t.isucon.pw.",
-	"aogawa0.t.isucon.pw.",
-	"ikedayosuke0.t.isucon.pw.",
-	"sakamotoyoichi0.t.isucon.pw.",
-	"yamadakaori1.t.isucon.pw.",
-	"ykobayashi1.t.isucon.pw.",
-	"fukudananami0.t.isucon.pw.",
-	"yasuhirohashimoto0.t.isucon.pw.",
-	"akemi670.t.isucon.pw.",
-	"rhasegawa0.t.isucon.pw.",
-	"naoto740.t.isucon.pw.",
-	"satoasuka0.t.isucon.pw.",
-	"lyamashita0.t.isucon.pw.",
-	"kumiko030.t.isucon.pw.",
-	"qikeda0.t.isucon.pw.",
-	"ptanaka0.t.isucon.pw.",
-	"fmatsuda0.t.isucon.pw.",
-	"akiramaeda0.t.isucon.pw.",
-	"yoichiyamamoto0.t.isucon.pw.",
-	"gsuzuki0.t.isucon.pw.",
-	"naokiikeda1.t.isucon.pw.",
-	"abekenichi0.t.isucon.pw.",
-	"maiyamamoto0.t.isucon.pw.",
-	"shimizukumiko0.t.isucon.pw.",
-	"zsato0.t.isucon.pw.",
-	"manabu050.t.isucon.pw.",
-	"shoheinishimura0.t.isucon.pw.",
-	"gyoshida0.t.isucon.pw.",
-	"chiyo790.t.isucon.pw.",
-	"hanakookada0.t.isucon.pw.",
-	"xota0.t.isucon.pw.",
-	"manabusakamoto0.t.isucon.pw.",
-	"jsato0.t.isucon.pw.",
-	"kenichi840.t.isucon.pw.",
-	"kobayashishota0.t.isucon.pw.",
-	"ainoue0.t.isucon.pw.",
-	"takuma380.t.isucon.pw.",
-	"dikeda0.t.isucon.pw.",
-	"dtanaka0.t.isucon.pw.",
-	"miturutakahashi0.t.isucon.pw.",
-	"fmurakami0.t.isucon.pw.",
-	"ishiiakira0.t.isucon.pw.",
-	"maayaokamoto0.t.isucon.pw.",
-	"asaito1.t.isucon.pw.",
-	"atsushi470.t.isucon.pw.",
-	"ekobayashi0.t.isucon.pw.",
-	"ukobayashi0.t.isucon.pw.",
-	"asaito2.t.isucon.pw.",
-	"hayashirei0.t.isucon.pw.",
-	"suzukisatomi0.t.isucon.pw.",
-	"yumiko100.t.isucon.pw.",
-	"okadahanako0.t.isucon.pw.",
-	"mtanaka0.t.isucon.pw.",
-	"hidekiyamamoto0.t.isucon.pw.",
-	"yamamotomituru0.t.isucon.pw.",
-	"kimuratomoya0.t.isucon.pw.",
-	"mikako780.t.isucon.pw.",
-	"pgoto0.t.isucon.pw.",
-	"harukawatanabe0.t.isucon.pw.",
-	"zkimura0.t.isucon.pw.",
-	"taichikimura0.t.isucon.pw.",
-	"moriminoru0.t.isucon.pw.",
-	"thayashi0.t.isucon.pw.",
-	"akira740.t.isucon.pw.",
-	"suzukiharuka1.t.isucon.pw.",
-	"yuta030.t.isucon.pw.",
-	"fyamamoto0.t.isucon.pw.",
-	"watanaberyosuke0.t.isucon.pw.",
-	"maayamatsumoto0.t.isucon.pw.",
-	"yoichiendo0.t.isucon.pw.",
-	"satokaori0.t.isucon.pw.",
-	"gmatsumoto0.t.isucon.pw.",
-	"rika250.t.isucon.pw.",
-	"jsasaki0.t.isucon.pw.",
-	"yutasaito0.t.isucon.pw.",
-	"kazuya810.t.isucon.pw.",
-	"manabukobayashi0.t.isucon.pw.",
-	"hashimotomikako0.t.isucon.pw.",
-	"fujiwarahideki0.t.isucon.pw.",
-	"yoichi730.t.isucon.pw.",
-	"takahashiryosuke0.t.isucon.pw.",
-	"tyamaguchi1.t.isucon.pw.",
-	"otaminoru0.t.isucon.pw.",
-	"momoko990.t.isucon.pw.",
-	"kazuyahasegawa0.t.isucon.pw.",
-	"mai770.t.isucon.pw.",
-	"aishii0.t.isucon.pw.",
-	"pokada0.t.isucon.pw.",
-	"cyamaguchi0.t.isucon.pw.",
-	"akemi500.t.isucon.pw.",
-	"jtanaka0.t.isucon.pw.",
-	"tnakagawa0.t.isucon.pw.",
-	"kondoyoko0.t.isucon.pw.",
-	"yoichiogawa0.t.isucon.pw.",
-	"hayashikenichi0.t.isucon.pw.",
-	"nanami990.t.isucon.pw.",
-	"hiroshiendo0.t.isucon.pw.",
-	"akirasuzuki0.t.isucon.pw.",
-	"maaya140.t.isucon.pw.",
-	"itomomoko0.t.isucon.pw.",
-	"satorika0.t.isucon.pw.",
-	"yoko110.t.isucon.pw.",
-	"wyamazaki0.t.isucon.pw.",
-	"yoko111.t.isucon.pw.",
-	"osato0.t.isucon.pw.",
-	"aokitaro0.t.isucon.pw.",
-	"gnakamura0.t.isucon.pw.",
-	"kobayashiyoko0.t.isucon.pw.",
-	"suzukinanami0.t.isucon.pw.",
-	"yosukeishikawa0.t.isucon.pw.",
-	"manabu450.t.isucon.pw.",
-	"naoki630.t.isucon.pw.",
-	"kimuranaoki0.t.isucon.pw.",
-	"kumiko770.t.isucon.pw.",
-	"moriyuta0.t.isucon.pw.",
-	"tsubasa300.t.isucon.pw.",
-	"hidekinakamura0.t.isucon.pw.",
-	"rei080.t.isucon.pw.",
-	"shoheiyoshida0.t.isucon.pw.",
-	"omatsumoto0.t.isucon.pw.",
-	"haruka260.t.isucon.pw.",
-	"takuma790.t.isucon.pw.",
-	"satoryosuke0.t.isucon.pw.",
-	"maedamaaya0.t.isucon.pw.",
-	"mikakosasaki0.t.isucon.pw.",
-	"vmiura0.t.isucon.pw.",
-	"rika570.t.isucon.pw.",
-	"myamaguchi0.t.isucon.pw.",
-	"minoru110.t.isucon.pw.",
-	"ishikawakana0.t.isucon.pw.",
-	"yoko300.t.isucon.pw.",
-	"tsuzuki0.t.isucon.pw.",
-	"kenichiwatanabe0.t.isucon.pw.",
-	"naokosakamoto0.t.isucon.pw.",
-	"fujitataichi0.t.isucon.pw.",
-	"naokigoto0.t.isucon.pw.",
-	"yuta210.t.isucon.pw.",
-	"sgoto0.t.isucon.pw.",
-	"momokoishii0.t.isucon.pw.",
-	"yoshidahideki0.t.isucon.pw.",
-	"takumainoue0.t.isucon.pw.",
-	"suzukiyuta0.t.isucon.pw.",
-	"manabu370.t.isucon.pw.",
-	"asuka120.t.isucon.pw.",
-	"manabu621.t.isucon.pw.",
-	"gogawa0.t.isucon.pw.",
-	"yamashitaosamu0.t.isucon.pw.",
-	"jnakamura1.t.isucon.pw.",
-	"atakahashi0.t.isucon.pw.",
-	"osamukobayashi0.t.isucon.pw.",
-	"satomisato0.t.isucon.pw.",
-	"satorika1.t.isucon.pw.",
-	"tanakahiroshi1.t.isucon.pw.",
-	"satoyuta0.t.isucon.pw.",
-	"mai710.t.isucon.pw.",
-	"nakamurakaori0.t.isucon.pw.",
-	"naoko440.t.isucon.pw.",
-	"jokamoto1.t.isucon.pw.",
-	"kobayashirei0.t.isucon.pw.",
-	"kyosukewatanabe0.t.isucon.pw.",
-	"yamaguchijun0.t.isucon.pw.",
-	"yoshidahiroshi0.t.isucon.pw.",
-	"miurahanako0.t.isucon.pw.",
-	"tsubasa790.t.isucon.pw.",
-	"hiroshitakahashi0.t.isucon.pw.",
-	"yukishimizu0.t.isucon.pw.",
-	"inouenaoki0.t.isucon.pw.",
-	"yuinakamura0.t.isucon.pw.",
-	"hashimotoakemi0.t.isucon.pw.",
-	"jwatanabe0.t.isucon.pw.",
-	"yosuke890.t.isucon.pw.",
-	"mikisato0.t.isucon.pw.",
-	"naotoinoue0.t.isucon.pw.",
-	"vinoue0.t.isucon.pw.",
-	"takuma170.t.isucon.pw.",
-	"yamashitatsubasa0.t.isucon.pw.",
-	"mituru180.t.isucon.pw.",
-	"ttanaka1.t.isucon.pw.",
-	"ogawataichi0.t.isucon.pw.",
-	"rsakamoto0.t.isucon.pw.",
-	"satoakemi0.t.isucon.pw.",
-	"lito0.t.isucon.pw.",
-	"suzukimaaya1.t.isucon.pw.",
-	"nakamurarei0.t.isucon.pw.",
-	"momokofujiwara0.t.isucon.pw.",
-	"yoko310.t.isucon.pw.",
-	"fujiwaramanabu0.t.isucon.pw.",
-	"takahashiosamu0.t.isucon.pw.",
-	"nakamurakazuya0.t.isucon.pw.",
-	"nnakamura0.t.isucon.pw.",
-	"akirasuzuki1.t.isucon.pw.",
-	"watanabenanami0.t.isucon.pw.",
-	"rikaito0.t.isucon.pw.",
-	"juntanaka0.t.isucon.pw.",
-	"tanakamiki0.t.isucon.pw.",
-	"ekato2.t.isucon.pw.",
-	"xnishimura0.t.isucon.pw.",
-	"ihayashi0.t.isucon.pw.",
-	"atsushi921.t.isucon.pw.",
-	"mikakoishikawa0.t.isucon.pw.",
-	"junmatsuda0.t.isucon.pw.",
-	"abejun0.t.isucon.pw.",
-	"katoshota0.t.isucon.pw.",
-	"shotaishikawa0.t.isucon.pw.",
-	"yukikimura0.t.isucon.pw.",
-	"xishii0.t.isucon.pw.",
-	"rei240.t.isucon.pw.",
-	"yutakobayashi0.t.isucon.pw.",
-	"kazuya380.t.isucon.pw.",
-	"nanamikobayashi0.t.isucon.pw.",
-	"zyamada0.t.isucon.pw.",
-	"inouehanako0.t.isucon.pw.",
-	"yutaota0.t.isucon.pw.",
-	"satomikato0.t.isucon.pw.",
-	"yoshidayasuhiro0.t.isucon.pw.",
-	"akemiikeda0.t.isucon.pw.",
-	"yoichikobayashi0.t.isucon.pw.",
-	"msuzuki0.t.isucon.pw.",
-	"ssato0.t.isucon.pw.",
-	"qnakamura0.t.isucon.pw.",
-	"ryoheikobayashi0.t.isucon.pw.",
-	"naoki880.t.isucon.pw.",
-	"sakamotosotaro0.t.isucon.pw.",
-	"haruka350.t.isucon.pw.",
-	"satonaoki0.t.isucon.pw.",
-	"jun430.t.isucon.pw.",
-	"sasakimai0.t.isucon.pw.",
-	"naoko880.t.isucon.pw.",
-	"rikakobayashi1.t.isucon.pw.",
-	"nanamikondo0.t.isucon.pw.",
-	"yoshidamai1.t.isucon.pw.",
-	"nakajimarei0.t.isucon.pw.",
-	"morimanabu0.t.isucon.pw.",
-	"tanakasayuri0.t.isucon.pw.",
-	"momoko040.t.isucon.pw.",
-	"kaori580.t.isucon.pw.",
-	"haruka100.t.isucon.pw.",
-	"akemi600.t.isucon.pw.",
-	"miki170.t.isucon.pw.",
-	"okamotokyosuke0.t.isucon.pw.",
-	"kondonaoto0.t.isucon.pw.",
-	"tsubasa610.t.isucon.pw.",
-	"ryoheiyamada0.t.isucon.pw.",
-	"naoki090.t.isucon.pw.",
-	"yamazakimikako0.t.isucon.pw.",
-	"minorusato0.t.isucon.pw.",
-	"chiyofujii0.t.isucon.pw.",
-	"takuma300.t.isucon.pw.",
-	"taroyamamoto0.t.isucon.pw.",
-	"suzukichiyo0.t.isucon.pw.",
-	"wishikawa0.t.isucon.pw.",
-	"reinakamura0.t.isucon.pw.",
-	"ayamazaki1.t.isucon.pw.",
-	"cogawa0.t.isucon.pw.",
-	"hasegawamituru0.t.isucon.pw.",
-	"cyamaguchi1.t.isucon.pw.",
-	"hasegawamomoko0.t.isucon.pw.",
-	"suzukishohei0.t.isucon.pw.",
-	"qyamazaki0.t.isucon.pw.",
-	"kyosuke370.t.isucon.pw.",
-	"mgoto0.t.isucon.pw.",
-	"yasuhiroogawa0.t.isucon.pw.",
-	"maaya250.t.isucon.pw.",
-	"kimurayumiko0.t.isucon.pw.",
-	"yoko460.t.isucon.pw.",
-	"atsushiyamaguchi0.t.isucon.pw.",
-	"yamadaasuka0.t.isucon.pw.",
-	"wyamaguchi0.t.isucon.pw.",
-	"katoyuta0.t.isucon.pw.",
-	"yoichi660.t.isucon.pw.",
-	"lkato0.t.isucon.pw.",
-	"tomoyatakahashi0.t.isucon.pw.",
-	"rika370.t.isucon.pw.",
-	"inouenaoto0.t.isucon.pw.",
-	"kondoshota0.t.isucon.pw.",
-	"taro820.t.isucon.pw.",
-	"maisasaki0.t.isucon.pw.",
-	"gyoshida1.t.isucon.pw.",
-	"manabu770.t.isucon.pw.",
-	"matsudayoichi0.t.isucon.pw.",
-	"nakajimamiki0.t.isucon.pw.",
-	"watanabeyoko0.t.isucon.pw.",
-	"vwatanabe1.t.isucon.pw.",
-	"yamaguchinaoto0.t.isucon.pw.",
-	"saitojun0.t.isucon.pw.",
-	"naotomori0.t.isucon.pw.",
-	"lito1.t.isucon.pw.",
-	"ryoheiikeda0.t.isucon.pw.",
-	"yuisasaki0.t.isucon.pw.",
-	"yishikawa0.t.isucon.pw.",
-	"yamazakimituru0.t.isucon.pw.",
-	"yumiko050.t.isucon.pw.",
-	"okadasayuri0.t.isucon.pw.",
-	"skobayashi0.t.isucon.pw.",
-	"rika740.t.isucon.pw.",
-	"yoko600.t.isucon.pw.",
-	"wsato0.t.isucon.pw.",
-	"taichi181.t.isucon.pw.",
-	"hiroshi800.t.isucon.pw.",
-	"yoko420.t.isucon.pw.",
-	"naokokondo0.t.isucon.pw.",
-	"sakamotorika0.t.isucon.pw.",
-	"hiroshiyoshida0.t.isucon.pw.",
-	"manabusato0.t.isucon.pw.",
-	"rei340.t.isucon.pw.",
-	"jsato1.t.isucon.pw.",
-	"satomikako0.t.isucon.pw.",
-	"murakamihiroshi0.t.isucon.pw.",
-	"asukaishii0.t.isucon.pw.",
-	"kana550.t.isucon.pw.",
-	"fyamaguchi1.t.isucon.pw.",
-	"miki190.t.isucon.pw.",
-	"dkimura0.t.isucon.pw.",
-	"ynakamura0.t.isucon.pw.",
-	"endomomoko0.t.isucon.pw.",
-	"itoyumiko0.t.isucon.pw.",
-	"yamashitayosuke0.t.isucon.pw.",
-	"yokokondo0.t.isucon.pw.",
-	"tsubasa470.t.isucon.pw.",
-	"matsudanaoko0.t.isucon.pw.",
-	"miuraatsushi0.t.isucon.pw.",
-	"miurataichi0.t.isucon.pw.",
-	"manabusato1.t.isucon.pw.",
-	"uhasegawa1.t.isucon.pw.",
-	"nanamimaeda0.t.isucon.pw.",
-	"katotsubasa0.t.isucon.pw.",
-	"eyamazaki0.t.isucon.pw.",
-	"wyamazaki1.t.isucon.pw.",
-	"junyamada0.t.isucon.pw.",
-	"akemigoto0.t.isucon.pw.",
-	"uokada0.t.isucon.pw.",
-	"nishii0.t.isucon.pw.",
-	"takumatanaka0.t.isucon.pw.",
-	"itomai0.t.isucon.pw.",
-	"hidekimaeda0.t.isucon.pw.",
-	"osuzuki0.t.isucon.pw.",
-	"harukasuzuki0.t.isucon.pw.",
-	"watanabeyuta0.t.isucon.pw.",
-	"kobayashikaori0.t.isucon.pw.",
-	"usuzuki1.t.isucon.pw.",
-	"gotokazuya0.t.isucon.pw.",
-	"kondotaichi0.t.isucon.pw.",
-	"nakamurasatomi0.t.isucon.pw.",
-	"ltakahashi0.t.isucon.pw.",
-	"jun240.t.isucon.pw.",
-	"sayuri860.t.isucon.pw.",
-	"jmurakami1.t.isucon.pw.",
-	"ftakahashi1.t.isucon.pw.",
-	"nanami660.t.isucon.pw.",
-	"otaasuka0.t.isucon.pw.",
-	"oito0.t.isucon.pw.",
-	"satomi720.t.isucon.pw.",
-	"ryosuke680.t.isucon.pw.",
-	"minoru370.t.isucon.pw.",
-	"matsumotokumiko0.t.isucon.pw.",
-	"sishii0.t.isucon.pw.",
-	"maedanaoko0.t.isucon.pw.",
-	"chiyo370.t.isucon.pw.",
-	"sasakitakuma0.t.isucon.pw.",
-	"reiikeda0.t.isucon.pw.",
-	"chiyo540.t.isucon.pw.",
-	"yamamotosayuri0.t.isucon.pw.",
-	"jota0.t.isucon.pw.",
-	"bsasaki0.t.isucon.pw.",
-	"bmori0.t.isucon.pw.",
-	"itojun0.t.isucon.pw.",
-	"atsushikondo0.t.isucon.pw.",
-	"jun320.t.isucon.pw.",
-	"osamusasaki0.t.isucon.pw.",
-	"suzukimaaya2.t.isucon.pw.",
-	"shimizuhideki0.t.isucon.pw.",
-	"endotomoya0.t.isucon.pw.",
-	"yamaguchimanabu0.t.isucon.pw.",
-	"rhashimoto0.t.isucon.pw.",
-	"rei590.t.isucon.pw.",
-	"yosuke490.t.isucon.pw.",
-	"tanakaryohei0.t.isucon.pw.",
-	"yukinakamura0.t.isucon.pw.",
-	"akira580.t.isucon.pw.",
-	"minoru400.t.isucon.pw.",
-	"yamaguchimomoko0.t.isucon.pw.",
-	"hanako410.t.isucon.pw.",
-	"hyamamoto0.t.isucon.pw.",
-	"maaya910.t.isucon.pw.",
-	"sayurikobayashi0.t.isucon.pw.",
-	"matsumotonaoko0.t.isucon.pw.",
-	"rei250.t.isucon.pw.",
-	"momoko980.t.isucon.pw.",
-	"asuka370.t.isucon.pw.",
-	"asuka100.t.isucon.pw.",
-	"yukiito0.t.isucon.pw.",
-	"mai500.t.isucon.pw.",
-	"manabutanaka0.t.isucon.pw.",
-	"taichinakamura0.t.isucon.pw.",
-	"csato0.t.isucon.pw.",
-	"mikiyoshida0.t.isucon.pw.",
-	"fujiiatsushi0.t.isucon.pw.",
-	"hayashinaoko0.t.isucon.pw.",
-	"kaorikobayashi0.t.isucon.pw.",
-	"lfujii0.t.isucon.pw.",
-	"wyamaguchi1.t.isucon.pw.",
-	"satomifujiwara0.t.isucon.pw.",
-	"gyamashita0.t.isucon.pw.",
-	"ryohei850.t.isucon.pw.",
-	"dnakamura0.t.isucon.pw.",
-	"rika040.t.isucon.pw.",
-	"satoyuta1.t.isucon.pw.",
-	"ynishimura0.t.isucon.pw.",
-	"tsubasa240.t.isucon.pw.",
-	"yukigoto0.t.isucon.pw.",
-	"satomi200.t.isucon.pw.",
-	"tsubasashimizu0.t.isucon.pw.",
-	"suzukiyumiko0.t.isucon.pw.",
-	"nakamurataichi0.t.isucon.pw.",
-	"yumiko070.t.isucon.pw.",
-	"shotafujiwara0.t.isucon.pw.",
-	"maedataro0.t.isucon.pw.",
-	"maifujita0.t.isucon.pw.",
-	"fujiwarayuki0.t.isucon.pw.",
-	"shohei240.t.isucon.pw.",
-	"aokirika0.t.isucon.pw.",
-	"hanako580.t.isucon.pw.",
-	"rgoto0.t.isucon.pw.",
-	"yuta100.t.isucon.pw.",
-	"yosukeishikawa1.t.isucon.pw.",
-	"dokada0.t.isucon.pw.",
-	"kimurahanako0.t.isucon.pw.",
-	"wnakagawa0.t.isucon.pw.",
-	"yukiyoshida0.t.isucon.pw.",
-	"naokitanaka0.t.isucon.pw.",
-	"ysaito0.t.isucon.pw.",
-	"miturusato0.t.isucon.pw.",
-	"naoki870.t.isucon.pw.",
-	"chiyo310.t.isucon.pw.",
-	"naokitanaka1.t.isucon.pw.",
-	"chiyonakamura0.t.isucon.pw.",
-	"msaito0.t.isucon.pw.",
-	"jsuzuki1.t.isucon.pw.",
-	"suzukihanako0.t.isucon.pw.",
-	"dokada1.t.isucon.pw.",
-	"rei850.t.isucon.pw.",
-	"kenichihashimoto0.t.isucon.pw.",
-	"fnakamura0.t.isucon.pw.",
-	"hasegawatakuma0.t.isucon.pw.",
-	"lyamamoto0.t.isucon.pw.",
-	"takuma600.t.isucon.pw.",
-	"manabutakahashi0.t.isucon.pw.",
-	"ryosukesakamoto0.t.isucon.pw.",
-	"itoharuka0.t.isucon.pw.",
-	"satomi130.t.isucon.pw.",
-	"tomoya450.t.isucon.pw.",
+// 既定のサブドメインのゾーンの頂点からの相対名(""は頂点そのもの)
+var defaultSubdomainLabels = []string{
+	"",
+	"ns1",
+	"pipe",
+	"test001",
+	"www",
+	"www1",
+	"www2",
+	"www3",
+	"www4",
+	"www5",
+	"mail",
+	"acr-nema",
+	"afpovertcp",
+	"afs3-bos",
+	"afs3-callback",
+	"afs3-fileserver",
+	"afs3-kaserver",
+	"afs3-prserver",
+	"afs3-rmtsys",
+	"afs3-update",
+	"afs3-vlserver",
+	"afs3-volser",
+	"amanda",
+	"amandaidx",
+	"amidxtape",
+	"amqp",
+	"amqps",
+	"asf-rmcp",
+	"asp",
+	"auth",
+	"babel",
+	"bacula-dir",
+	"bacula-fd",
+	"bacula-sd",
+	"bbs",
+	"bgp",
+	"bgpd",
+	"biff",
+	"binkp",
+	"bootpc",
+	"bootps",
+	"canna",
+	"cfengine",
+	"chargen",
+	"cisco-sccp",
+	"clc-build-daemon",
+	"clearcase",
+	"cmip-agent",
+	"cmip-man",
+	"codaauth2",
+	"codasrv",
+	"codasrv-se",
+	"csync2",
+	"cvspserver",
+	"daap",
+	"datametrics",
+	"daytime",
+	"db-lsp",
+	"dcap",
+	"dhcpv6-client",
+	"dhcpv6-server",
+	"dicom",
+	"dict",
+	"dircproxy",
+	"discard",
+	"distcc",
+	"domain",
+	"domain-s",
+	"echo",
+	"epmap",
+	"epmd",
+	"exec",
+	"f5-globalsite",
+	"f5-iquery",
+	"fax",
+	"fido",
+	"finger",
+	"font-service",
+	"freeciv",
+	"fsp",
+	"ftp",
+	"ftp-data",
+	"ftps",
+	"ftps-data",
+	"gdomap",
+	"gds-db",
+	"git",
+	"gnunet",
+	"gnutella-rtr",
+	"gnutella-svc",
+	"gopher",
+	"gpsd",
+	"gris",
+	"groupwise",
+	"gsidcap",
+	"gsiftp",
+	"gsigatekeeper",
+	"hkp",
+	"http",
+	"http-alt",
+	"https",
+	"hylafax",
+	"iax",
+	"icpv2",
+	"imap2",
+	"imaps",
+	"ingreslock",
+	"ipp",
+	"iprop",
+	"ipsec-nat-t",
+	"ipx",
+	"ircd",
+	"ircs-u",
+	"isakmp",
+	"iscsi-target",
+	"isisd",
+	"isns",
+	"iso-tsap",
+	"kamandakerberos",
+	"kerberos-adm",
+	"kerberos-master",
+	"kerberos4",
+	"kermit",
+	"klogin",
+	"kpasswd",
+	"krb-prop",
+	"kshell",
+	"l2f",
+	"ldap",
+	"ldaps",
+	"ldp",
+	"login",
+	"lotusnote",
+	"mailq",
+	"mdns",
+	"microsoft-ds",
+	"moira-db",
+	"moira-update",
+	"moira-ureg",
+	"mon",
+	"ms-sql-m",
+	"ms-sql-s",
+	"ms-wbt-server",
+	"mtn",
+	"munin",
+	"mysql",
+	"mysql-proxy",
+	"nbd",
+	"nbp",
+	"netbios-dgm",
+	"netbios-ns",
+	"netbios-ssn",
+	"netstat",
+	"nfs",
+	"nntp",
+	"nntps",
+	"nqs",
+	"nrpe",
+	"nsca",
+	"ntalk",
+	"ntp",
+	"ntske",
+	"nut",
+	"omniorb",
+	"openvpn",
+	"ospf6d",
+	"ospfapi",
+	"ospfd",
+	"passwd-server",
+	"pawserv",
+	"pop3",
+	"pop3s",
+	"poppassd",
+	"postgresql",
+	"predict",
+	"printer",
+	"proofd",
+	"ptp-event",
+	"ptp-general",
+	"puppet",
+	"qmqp",
+	"qmtp",
+	"qotd",
+	"radius",
+	"radius-acct",
+	"radmin-port",
+	"redis",
+	"remctl",
+	"ripd",
+	"ripngd",
+	"rmiregistry",
+	"rmtcfg",
+	"rootd",
+	"route",
+	"rpc2portmap",
+	"rplay",
+	"rsync",
+	"rtcm-sc104",
+	"rtmp",
+	"rtsp",
+	"sa-msg-port",
+	"saft",
+	"sane-port",
+	"sge-execd",
+	"sge-qmaster",
+	"sgi-cad",
+	"sgi-cmsd",
+	"sgi-crsd",
+	"sgi-gcd",
+	"shell",
+	"sieve",
+	"silc",
+	"sip",
+	"sip-tls",
+	"skkserv",
+	"smtp",
+	"smux",
+	"snmp",
+	"snmp-trap",
+	"snpp",
+	"socks",
+	"spamd",
+	"ssh",
+	"submission",
+	"submissions",
+	"sunrpc",
+	"supfiledbg",
+	"supfilesrv",
+	"suucp",
+	"svn",
+	"svrloc",
+	"syslog",
+	"syslog-tls",
+	"sysrqd",
+	"systat",
+	"tacacs",
+	"talk",
+	"tcpmux",
+	"telnet",
+	"telnets",
+	"tfido",
+	"tftp",
+	"time",
+	"tinc",
+	"tproxy",
+	"uucp",
+	"venus",
+	"venus-se",
+	"webmin",
+	"who",
+	"whois",
+	"wnn6",
+	"x11",
+	"x11-1",
+	"x11-2",
+	"x11-3",
+	"x11-4",
+	"x11-5",
+	"x11-6",
+	"x11-7",
+	"xdmcp",
+	"xinetd",
+	"xmms2",
+	"xmpp-client",
+	"xmpp-server",
+	"xtel",
+	"xtelw",
+	"z3950",
+	"zabbix-agent",
+	"zabbix-trapper",
+	"zebra",
+	"zebrasrv",
+	"zephyr-clt",
+	"zephyr-hm",
+	"zephyr-srv",
+	"zip",
+	"zope",
+	"zope-ftp",
+	"zserv",
+	"ayamazaki0",
+	"yoshidamiki0",
+	"hidekimurakami0",
+	"akemikobayashi0",
+	"eishikawa0",
+	"tomoya100",
+	"kobayashiminoru0",
+	"yamadakaori0",
+	"tanakahanako0",
+	"taro660",
+	"hashimotokenichi0",
+	"yuta330",
+	"kenichinakamura0",
+	"taichikato0",
+	"wfujita0",
+	"saitotakuma0",
+	"maayafujiwara0",
+	"akira680",
+	"vmaeda0",
+	"jnakamura0",
+	"suzukitsubasa0",
+	"yoshidatomoya0",
+	"qendo0",
+	"haruka030",
+	"saitotakuma1",
+	"bsuzuki0",
+	"shohei720",
+	"naoko980",
+	"suzukiryohei0",
+	"kobayashisayuri0",
+	"ykobayashi0",
+	"asuzuki0",
+	"sotaro880",
+	"nyamaguchi0",
+	"momokohashimoto0",
+	"suzukinaoki0",
+	"wgoto0",
+	"tomoya540",
+	"fujitayoichi0",
+	"kaorikato0",
+	"chiyo810",
+	"yito0",
+	"tomoyakato0",
+	"ryosukeabe0",
+	"smatsumoto0",
+	"vwatanabe0",
+	"sayuri650",
+	"takahashinaoto0",
+	"hiroshisuzuki0",
+	"xyamamoto0",
+	"osaito0",
+	"esato0",
+	"oshimizu0",
+	"yamadamituru0",
+	"wmori0",
+	"saitorei0",
+	"kimuramiki0",
+	"sasakiyosuke0",
+	"kumiko410",
+	"qkobayashi0",
+	"akondo0",
+	"ywatanabe0",
+	"otanaoki0",
+	"naotosasaki0",
+	"momokosuzuki0",
+	"maayayoshida0",
+	"hashimotoasuka0",
+	"maayanakagawa0",
+	"ltanaka0",
+	"jyamada0",
+	"yasuhiro650",
+	"hiroshitanaka0",
+	"yamashitaakemi0",
+	"hidekiishikawa0",
+	"kanatakahashi0",
+	"akemiito0",
+	"manabu800",
+	"shota790",
+	"naokoaoki0",
+	"gyamamoto0",
+	"xyamaguchi0",
+	"katokumiko0",
+	"xyamada0",
+	"qyamashita0",
+	"pwatanabe0",
+	"ryoheiishikawa0",
+	"nakamuraharuka0",
+	"pfukuda0",
+	"saitoyosuke0",
+	"morijun0",
+	"hiroshiokamoto0",
+	"kazuyahayashi0",
+	"anakajima0",
+	"maaya110",
+	"kazuya250",
+	"kondoakira0",
+	"atsushi920",
+	"hanakokondo0",
+	"naoki220",
+	"hiroshi180",
+	"jun820",
+	"onakamura0",
+	"takuma040",
+	"kobayashiyasuhiro0",
+	"reiwatanabe0",
+	"hideki630",
+	"yutayoshida0",
+	"yutamori0",
+	"ryosukeyamamoto0",
+	"manabuota0",
+	"nsakamoto0",
+	"wtanaka0",
+	"inakamura0",
+	"ymori0",
+	"nfujii0",
+	"osamu950",
+	"yuinakagawa0",
+	"uhayashi0",
+	"momoko070",
+	"myamada0",
+	"zyoshida0",
+	"watanabesatomi0",
+	"watanabemanabu0",
+	"momokosuzuki1",
+	"jokada0",
+	"hanako640",
+	"rmatsumoto0",
+	"maayasasaki0",
+	"ryohei450",
+	"takuma570",
+	"yokotanaka0",
+	"mai270",
+	"rikasakamoto0",
+	"taro150",
+	"suzukimomoko0",
+	"watanabeyoichi0",
+	"yuki620",
+	"tsubasainoue0",
+	"yokotakahashi0",
+	"yumikohayashi0",
+	"yamaguchiyumiko0",
+	"kkato0",
+	"minoruyoshida0",
+	"tmurakami0",
+	"hasegawanaoto0",
+	"kumikowatanabe0",
+	"lota0",
+	"yoichi440",
+	"sayurikondo0",
+	"xogawa0",
+	"naoki250",
+	"eokada0",
+	"satomiyamamoto0",
+	"asukamaeda0",
+	"momokowatanabe0",
+	"nanamisuzuki0",
+	"gotomanabu0",
+	"maiyamazaki0",
+	"junito0",
+	"aokikazuya0",
+	"shohei040",
+	"momokotanaka0",
+	"atsushimatsumoto0",
+	"taichifukuda0",
+	"haruka630",
+	"tanakashohei0",
+	"qnishimura0",
+	"mkondo0",
+	"kimurayui0",
+	"tomoyanakajima0",
+	"naoko310",
+	"yukiokada0",
+	"tsubasasuzuki0",
+	"yutatakahashi0",
+	"kyosuke140",
+	"tomoya190",
+	"ryohei610",
+	"fukudahiroshi0",
+	"tyamaguchi0",
+	"rsasaki0",
+	"satoyoichi0",
+	"rikawatanabe0",
+	"vokada0",
+	"mituru070",
+	"nakajimayui0",
+	"nanamiota0",
+	"smatsuda0",
+	"suzukijun0",
+	"saitosayuri0",
+	"haruka730",
+	"yamamotoakemi0",
+	"satoyosuke0",
+	"maayasato0",
+	"nanami830",
+	"kanaokamoto0",
+	"usuzuki0",
+	"manabusasaki0",
+	"kenichi870",
+	"tomoyasato0",
+	"mituruhayashi0",
+	"junnishimura0",
+	"fujiwaramituru0",
+	"yoshidamai0",
+	"yosuke350",
+	"xwatanabe0",
+	"naokimatsumoto0",
+	"kenichi170",
+	"naoko340",
+	"nakajimasotaro0",
+	"eito0",
+	"kenichi470",
+	"qabe0",
+	"junkondo0",
+	"yosukewatanabe0",
+	"ryosuke040",
+	"itosotaro0",
+	"kimuramikako0",
+	"reiogawa0",
+	"myamada1",
+	"yamazakisayuri0",
+	"gotoakemi0",
+	"shohei660",
+	"phasegawa0",
+	"kana990",
+	"yosuke710",
+	"rika420",
+	"ikedajun0",
+	"takuma250",
+	"yoichihayashi0",
+	"yoichi441",
+	"zmiura0",
+	"ftakahashi0",
+	"oinoue0",
+	"osamu920",
+	"takahashikumiko0",
+	"tsubasamaeda0",
+	"ryosuke220",
+	"yoichimurakami0",
+	"abeyumiko0",
+	"tishii0",
+	"vkato0",
+	"mituru710",
+	"asukamurakami0",
+	"yuiwatanabe0",
+	"jokamoto0",
+	"akirafujiwara0",
+	"lgoto0",
+	"akiratakahashi0",
+	"lhashimoto0",
+	"ogawarika0",
+	"manabu620",
+	"osamuyamaguchi0",
+	"fujiwararei0",
+	"yosukewatanabe1",
+	"asuka500",
+	"otamaaya0",
+	"okamotonaoko0",
+	"tokamoto0",
+	"saitomituru0",
+	"suzukitakuma0",
+	"suzukimaaya0",
+	"nanamitanaka0",
+	"suzukikana0",
+	"sakamotoshohei0",
+	"kanayamamoto0",
+	"xtakahashi0",
+	"wsuzuki0",
+	"taichi990",
+	"saitoyuki0",
+	"rei050",
+	"nmaeda0",
+	"csuzuki0",
+	"takahashiatsushi0",
+	"yukifukuda0",
+	"yumiko680",
+	"yamadayoko0",
+	"abeosamu0",
+	"taro210",
+	"katomai0",
+	"hasegawakumiko0",
+	"vhashimoto0",
+	"tanakamai0",
+	"hmori0",
+	"naokoyamaguchi0",
+	"yamaguchiyuta0",
+	"iyamamoto0",
+	"yutanakajima0",
+	"kyosukesasaki0",
+	"satosatomi0",
+	"kkato1",
+	"kumiko980",
+	"jishii0",
+	"yutafujiwara0",
+	"fukudamomoko0",
+	"kumikokobayashi0",
+	"naoki350",
+	"sayuri230",
+	"morijun1",
+	"mituru840",
+	"eyoshida0",
+	"taichi460",
+	"esasaki0",
+	"yumikonakamura0",
+	"akemi540",
+	"kazuya770",
+	"suzukiharuka0",
+	"harukagoto0",
+	"vkato1",
+	"bmatsumoto0",
+	"yamazakikaori0",
+	"yamashitasatomi0",
+	"fujiimikako0",
+	"ekato0",
+	"asukaito0",
+	"aokimiki0",
+	"katotaichi0",
+	"harukasasaki0",
+	"manabuyamamoto0",
+	"etanaka0",
+	"nakagawamituru0",
+	"hiroshikobayashi0",
+	"satomi900",
+	"akira180",
+	"manabu710",
+	"minoru160",
+	"fujiwarataro0",
+	"tomoya240",
+	"taichinakajima0",
+	"atsushitakahashi0",
+	"yoichi150",
+	"fito0",
+	"satomitakahashi0",
+	"akemi230",
+	"akato0",
+	"asuka250",
+	"atsushiinoue0",
+	"sasakirei0",
+	"yamadamiki0",
+	"taro890",
+	"yamamotohiroshi0",
+	"vtakahashi0",
+	"yoichiyoshida0",
+	"nishimurashohei0",
+	"zsasaki0",
+	"shimizumaaya0",
+	"inoueosamu0",
+	"hsato0",
+	"kimuraharuka0",
+	"takumayoshida0",
+	"ikedahideki0",
+	"kobayashitsubasa0",
+	"jmurakami0",
+	"kaori320",
+	"suzukimiki0",
+	"gtanaka0",
+	"shota220",
+	"fyamada0",
+	"nishimuraminoru0",
+	"ttanaka0",
+	"satomi280",
+	"maaya390",
+	"snakamura0",
+	"okamototsubasa0",
+	"etakahashi0",
+	"taro500",
+	"yasuhirotakahashi0",
+	"uhasegawa0",
+	"miki470",
+	"ryosukeito0",
+	"kanasaito0",
+	"dyamashita0",
+	"taichi180",
+	"iyamazaki0",
+	"yui950",
+	"kumikomaeda0",
+	"yuiwatanabe1",
+	"suzukishota0",
+	"vsuzuki0",
+	"tanakahiroshi0",
+	"yosukekimura0",
+	"saitoryosuke0",
+	"ymori1",
+	"xnakajima0",
+	"zyamazaki0",
+	"mikakoyamamoto0",
+	"tanakahideki0",
+	"ekato1",
+	"momokomori0",
+	"tsubasa260",
+	"mikako290",
+	"yoko160",
+	"yuki480",
+	"hasegawakumiko1",
+	"itokana0",
+	"fkobayashi0",
+	"tomoya630",
+	"yuki730",
+	"vyamaguchi0",
+	"tshimizu0",
+	"pota0",
+	"hiroshi960",
+	"katoyui0",
+	"mkondo1",
+	"rika500",
+	"einoue0",
+	"yamashitayumiko0",
+	"kazuya680",
+	"asukakobayashi0",
+	"miki320",
+	"yumikosuzuki0",
+	"naotoito0",
+	"esuzuki0",
+	"hiroshiaoki0",
+	"vishikawa0",
+	"akiraito0",
+	"mikako020",
+	"miurasatomi0",
+	"etakahashi1",
+	"tarosato0",
+	"matsumotomanabu0",
+	"naoko560",
+	"kaori570",
+	"minoru210",
+	"jsuzuki0",
+	"tanakashohei1",
+	"tyamada0",
+	"kenichi160",
+	"shotayamada0",
+	"hiroshi070",
+	"hidekisakamoto0",
+	"naokiikeda0",
+	"nakagawamaaya0",
+	"hiroshitanaka1",
+	"yamashitamaaya0",
+	"momokoabe0",
+	"vito0",
+	"sakamotomikako0",
+	"asaito0",
+	"ryosukewatanabe0",
+	"nanami140",
+	"tanakahanako1",
+	"rei390",
+	"rito0",
+	"akemiinoue0",
+	"jendo0",
+	"cwatanabe0",
+	"rikakobayashi0",
+	"kaoriokamoto0",
+	"momoko350",
+	"sayuri130",
+	"kaoritanaka0",
+	"mikakokobayashi0",
+	"naoto500",
+	"suzukinaoto0",
+	"myamamoto0",
+	"yuta090",
+	"asato0",
+	"chiyo580",
+	"lmatsumoto0",
+	"kaori190",
+	"mai860",
+	"naokokimura0",
+	"tomoya400",
+	"dota0",
+	"osamu590",
+	"takahashitomoya0",
+	"shimizuakemi0",
+	"endokenichi0",
+	"yoshidaryohei0",
+	"ryosukemurakami0",
+	"reitanaka0",
+	"akemi910",
+	"enakamura0",
+	"ohayashi0",
+	"takumasuzuki0",
+	"suzukijun1",
+	"bsaito0",
+	"maifujiwara0",
+	"ikedayoko0",
+	"kimurakenichi0",
+	"kyosuke210",
+	"shota870",
+	"fyamaguchi0",
+	"miki730",
+	"sayurihashimoto0",
+	"naoki460",
+	"satoyumiko0",
+	"minoruhashimoto0",
+	"nanamimurakami0",
+	"mai600",
+	"taro340",
+	"yosuke950",
+	"rokada0",
+	"nanami710",
+	"zishikawa0",
+	"yutawatanabe0",
+	"suzukiosamu0",
+	"mituru130",
+	"taichi000",
+	"chiyoyamamoto0",
+	"yoshidakyosuke0",
+	"vyamazaki0",
+	"wwatanabe0",
+	"ykimura0",
+	"tanakahideki1",
+	"minoruito0",
+	"shotamatsumoto0",
+	"watanabehiroshi0",
+	"taichi560",
+	"nanami090",
+	"akira800",
+	"suzukiyasuhiro0",
+	"momokofukuda0",
+	"kana350",
+	"tanakanaoto0",
+	"akemi800",
+	"tanakakenichi0",
+	"oyoshida0",
+	"yutamatsumoto0",
+	"hiroshisuzuki1",
+	"yamaguchikazuya0",
+	"yoko800",
+	"lnakamura0",
+	"esato1",
+	"atsushitakahashi1",
+	"eshimizu0",
+	"reitanaka1",
+	"yuki500",
+	"tanakashohei2",
+	"akiranishimura0",
+	"naokiyamamoto0",
+	"shimizuatsushi0",
+	"winoue0",
+	"hiroshifujita0",
+	"mikako380",
+	"gotoshohei0",
+	"yumikoyamamoto0",
+	"gmatsuda0",
+	"kumikokobayashi1",
+	"tsubasatakahashi0",
+	"yyamamoto0",
+	"nakamurajun0",
+	"yasuhirokato0",
+	"vsuzuki1",
+	"kobayashihideki0",
+	"murakamiosamu0",
+	"mikako340",
+	"takumaokada0",
+	"osamusato0",
+	"aogawa0",
+	"ikedayosuke0",
+	"sakamotoyoichi0",
+	"yamadakaori1",
+	"ykobayashi1",
+	"fukudananami0",
+	"yasuhirohashimoto0",
+	"akemi670",
+	"rhasegawa0",
+	"naoto740",
+	"satoasuka0",
+	"lyamashita0",
+	"kumiko030",
+	"qikeda0",
+	"ptanaka0",
+	"fmatsuda0",
+	"akiramaeda0",
+	"yoichiyamamoto0",
+	"gsuzuki0",
+	"naokiikeda1",
+	"abekenichi0",
+	"maiyamamoto0",
+	"shimizukumiko0",
+	"zsato0",
+	"manabu050",
+	"shoheinishimura0",
+	"gyoshida0",
+	"chiyo790",
+	"hanakookada0",
+	"xota0",
+	"manabusakamoto0",
+	"jsato0",
+	"kenichi840",
+	"kobayashishota0",
+	"ainoue0",
+	"takuma380",
+	"dikeda0",
+	"dtanaka0",
+	"miturutakahashi0",
+	"fmurakami0",
+	"ishiiakira0",
+	"maayaokamoto0",
+	"asaito1",
+	"atsushi470",
+	"ekobayashi0",
+	"ukobayashi0",
+	"asaito2",
+	"hayashirei0",
+	"suzukisatomi0",
+	"yumiko100",
+	"okadahanako0",
+	"mtanaka0",
+	"hidekiyamamoto0",
+	"yamamotomituru0",
+	"kimuratomoya0",
+	"mikako780",
+	"pgoto0",
+	"harukawatanabe0",
+	"zkimura0",
+	"taichikimura0",
+	"moriminoru0",
+	"thayashi0",
+	"akira740",
+	"suzukiharuka1",
+	"yuta030",
+	"fyamamoto0",
+	"watanaberyosuke0",
+	"maayamatsumoto0",
+	"yoichiendo0",
+	"satokaori0",
+	"gmatsumoto0",
+	"rika250",
+	"jsasaki0",
+	"yutasaito0",
+	"kazuya810",
+	"manabukobayashi0",
+	"hashimotomikako0",
+	"fujiwarahideki0",
+	"yoichi730",
+	"takahashiryosuke0",
+	"tyamaguchi1",
+	"otaminoru0",
+	"momoko990",
+	"kazuyahasegawa0",
+	"mai770",
+	"aishii0",
+	"pokada0",
+	"cyamaguchi0",
+	"akemi500",
+	"jtanaka0",
+	"tnakagawa0",
+	"kondoyoko0",
+	"yoichiogawa0",
+	"hayashikenichi0",
+	"nanami990",
+	"hiroshiendo0",
+	"akirasuzuki0",
+	"maaya140",
+	"itomomoko0",
+	"satorika0",
+	"yoko110",
+	"wyamazaki0",
+	"yoko111",
+	"osato0",
+	"aokitaro0",
+	"gnakamura0",
+	"kobayashiyoko0",
+	"suzukinanami0",
+	"yosukeishikawa0",
+	"manabu450",
+	"naoki630",
+	"kimuranaoki0",
+	"kumiko770",
+	"moriyuta0",
+	"tsubasa300",
+	"hidekinakamura0",
+	"rei080",
+	"shoheiyoshida0",
+	"omatsumoto0",
+	"haruka260",
+	"takuma790",
+	"satoryosuke0",
+	"maedamaaya0",
+	"mikakosasaki0",
+	"vmiura0",
+	"rika570",
+	"myamaguchi0",
+	"minoru110",
+	"ishikawakana0",
+	"yoko300",
+	"tsuzuki0",
+	"kenichiwatanabe0",
+	"naokosakamoto0",
+	"fujitataichi0",
+	"naokigoto0",
+	"yuta210",
+	"sgoto0",
+	"momokoishii0",
+	"yoshidahideki0",
+	"takumainoue0",
+	"suzukiyuta0",
+	"manabu370",
+	"asuka120",
+	"manabu621",
+	"gogawa0",
+	"yamashitaosamu0",
+	"jnakamura1",
+	"atakahashi0",
+	"osamukobayashi0",
+	"satomisato0",
+	"satorika1",
+	"tanakahiroshi1",
+	"satoyuta0",
+	"mai710",
+	"nakamurakaori0",
+	"naoko440",
+	"jokamoto1",
+	"kobayashirei0",
+	"kyosukewatanabe0",
+	"yamaguchijun0",
+	"yoshidahiroshi0",
+	"miurahanako0",
+	"tsubasa790",
+	"hiroshitakahashi0",
+	"yukishimizu0",
+	"inouenaoki0",
+	"yuinakamura0",
+	"hashimotoakemi0",
+	"jwatanabe0",
+	"yosuke890",
+	"mikisato0",
+	"naotoinoue0",
+	"vinoue0",
+	"takuma170",
+	"yamashitatsubasa0",
+	"mituru180",
+	"ttanaka1",
+	"ogawataichi0",
+	"rsakamoto0",
+	"satoakemi0",
+	"lito0",
+	"suzukimaaya1",
+	"nakamurarei0",
+	"momokofujiwara0",
+	"yoko310",
+	"fujiwaramanabu0",
+	"takahashiosamu0",
+	"nakamurakazuya0",
+	"nnakamura0",
+	"akirasuzuki1",
+	"watanabenanami0",
+	"rikaito0",
+	"juntanaka0",
+	"tanakamiki0",
+	"ekato2",
+	"xnishimura0",
+	"ihayashi0",
+	"atsushi921",
+	"mikakoishikawa0",
+	"junmatsuda0",
+	"abejun0",
+	"katoshota0",
+	"shotaishikawa0",
+	"yukikimura0",
+	"xishii0",
+	"rei240",
+	"yutakobayashi0",
+	"kazuya380",
+	"nanamikobayashi0",
+	"zyamada0",
+	"inouehanako0",
+	"yutaota0",
+	"satomikato0",
+	"yoshidayasuhiro0",
+	"akemiikeda0",
+	"yoichikobayashi0",
+	"msuzuki0",
+	"ssato0",
+	"qnakamura0",
+	"ryoheikobayashi0",
+	"naoki880",
+	"sakamotosotaro0",
+	"haruka350",
+	"satonaoki0",
+	"jun430",
+	"sasakimai0",
+	"naoko880",
+	"rikakobayashi1",
+	"nanamikondo0",
+	"yoshidamai1",
+	"nakajimarei0",
+	"morimanabu0",
+	"tanakasayuri0",
+	"momoko040",
+	"kaori580",
+	"haruka100",
+	"akemi600",
+	"miki170",
+	"okamotokyosuke0",
+	"kondonaoto0",
+	"tsubasa610",
+	"ryoheiyamada0",
+	"naoki090",
+	"yamazakimikako0",
+	"minorusato0",
+	"chiyofujii0",
+	"takuma300",
+	"taroyamamoto0",
+	"suzukichiyo0",
+	"wishikawa0",
+	"reinakamura0",
+	"ayamazaki1",
+	"cogawa0",
+	"hasegawamituru0",
+	"cyamaguchi1",
+	"hasegawamomoko0",
+	"suzukishohei0",
+	"qyamazaki0",
+	"kyosuke370",
+	"mgoto0",
+	"yasuhiroogawa0",
+	"maaya250",
+	"kimurayumiko0",
+	"yoko460",
+	"atsushiyamaguchi0",
+	"yamadaasuka0",
+	"wyamaguchi0",
+	"katoyuta0",
+	"yoichi660",
+	"lkato0",
+	"tomoyatakahashi0",
+	"rika370",
+	"inouenaoto0",
+	"kondoshota0",
+	"taro820",
+	"maisasaki0",
+	"gyoshida1",
+	"manabu770",
+	"matsudayoichi0",
+	"nakajimamiki0",
+	"watanabeyoko0",
+	"vwatanabe1",
+	"yamaguchinaoto0",
+	"saitojun0",
+	"naotomori0",
+	"lito1",
+	"ryoheiikeda0",
+	"yuisasaki0",
+	"yishikawa0",
+	"yamazakimituru0",
+	"yumiko050",
+	"okadasayuri0",
+	"skobayashi0",
+	"rika740",
+	"yoko600",
+	"wsato0",
+	"taichi181",
+	"hiroshi800",
+	"yoko420",
+	"naokokondo0",
+	"sakamotorika0",
+	"hiroshiyoshida0",
+	"manabusato0",
+	"rei340",
+	"jsato1",
+	"satomikako0",
+	"murakamihiroshi0",
+	"asukaishii0",
+	"kana550",
+	"fyamaguchi1",
+	"miki190",
+	"dkimura0",
+	"ynakamura0",
+	"endomomoko0",
+	"itoyumiko0",
+	"yamashitayosuke0",
+	"yokokondo0",
+	"tsubasa470",
+	"matsudanaoko0",
+	"miuraatsushi0",
+	"miurataichi0",
+	"manabusato1",
+	"uhasegawa1",
+	"nanamimaeda0",
+	"katotsubasa0",
+	"eyamazaki0",
+	"wyamazaki1",
+	"junyamada0",
+	"akemigoto0",
+	"uokada0",
+	"nishii0",
+	"takumatanaka0",
+	"itomai0",
+	"hidekimaeda0",
+	"osuzuki0",
+	"harukasuzuki0",
+	"watanabeyuta0",
+	"kobayashikaori0",
+	"usuzuki1",
+	"gotokazuya0",
+	"kondotaichi0",
+	"nakamurasatomi0",
+	"ltakahashi0",
+	"jun240",
+	"sayuri860",
+	"jmurakami1",
+	"ftakahashi1",
+	"nanami660",
+	"otaasuka0",
+	"oito0",
+	"satomi720",
+	"ryosuke680",
+	"minoru370",
+	"matsumotokumiko0",
+	"sishii0",
+	"maedanaoko0",
+	"chiyo370",
+	"sasakitakuma0",
+	"reiikeda0",
+	"chiyo540",
+	"yamamotosayuri0",
+	"jota0",
+	"bsasaki0",
+	"bmori0",
+	"itojun0",
+	"atsushikondo0",
+	"jun320",
+	"osamusasaki0",
+	"suzukimaaya2",
+	"shimizuhideki0",
+	"endotomoya0",
+	"yamaguchimanabu0",
+	"rhashimoto0",
+	"rei590",
+	"yosuke490",
+	"tanakaryohei0",
+	"yukinakamura0",
+	"akira580",
+	"minoru400",
+	"yamaguchimomoko0",
+	"hanako410",
+	"hyamamoto0",
+	"maaya910",
+	"sayurikobayashi0",
+	"matsumotonaoko0",
+	"rei250",
+	"momoko980",
+	"asuka370",
+	"asuka100",
+	"yukiito0",
+	"mai500",
+	"manabutanaka0",
+	"taichinakamura0",
+	"csato0",
+	"mikiyoshida0",
+	"fujiiatsushi0",
+	"hayashinaoko0",
+	"kaorikobayashi0",
+	"lfujii0",
+	"wyamaguchi1",
+	"satomifujiwara0",
+	"gyamashita0",
+	"ryohei850",
+	"dnakamura0",
+	"rika040",
+	"satoyuta1",
+	"ynishimura0",
+	"tsubasa240",
+	"yukigoto0",
+	"satomi200",
+	"tsubasashimizu0",
+	"suzukiyumiko0",
+	"nakamurataichi0",
+	"yumiko070",
+	"shotafujiwara0",
+	"maedataro0",
+	"maifujita0",
+	"fujiwarayuki0",
+	"shohei240",
+	"aokirika0",
+	"hanako580",
+	"rgoto0",
+	"yuta100",
+	"yosukeishikawa1",
+	"dokada0",
+	"kimurahanako0",
+	"wnakagawa0",
+	"yukiyoshida0",
+	"naokitanaka0",
+	"ysaito0",
+	"miturusato0",
+	"naoki870",
+	"chiyo310",
+	"naokitanaka1",
+	"chiyonakamura0",
+	"msaito0",
+	"jsuzuki1",
+	"suzukihanako0",
+	"dokada1",
+	"rei850",
+	"kenichihashimoto0",
+	"fnakamura0",
+	"hasegawatakuma0",
+	"lyamamoto0",
+	"takuma600",
+	"manabutakahashi0",
+	"ryosukesakamoto0",
+	"itoharuka0",
+	"satomi130",
+	"tomoya450",
 }
@@ -0,0 +1,395 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	dnsZoneEnvKey        = "ISUCON13_DNS_ZONE"
+	dnsNameserversEnvKey = "ISUCON13_DNS_NAMESERVERS"
+	dnsAnswerTTLEnvKey   = "ISUCON13_DNS_ANSWER_TTL"
+	dnsNSTTLEnvKey       = "ISUCON13_DNS_NS_TTL"
+	dnsNegativeTTLEnvKey = "ISUCON13_DNS_NEGATIVE_TTL"
+	dnsSOAMboxEnvKey     = "ISUCON13_DNS_SOA_MBOX"
+	dnsNegativeEnvKey    = "ISUCON13_DNS_NEGATIVE_POLICY"
+
+	dnsRRLResponsesEnvKey  = "ISUCON13_DNS_RRL_RESPONSES_PER_SECOND"
+	dnsRRLErrorsEnvKey     = "ISUCON13_DNS_RRL_ERRORS_PER_SECOND"
+	dnsRRLSlipEnvKey       = "ISUCON13_DNS_RRL_SLIP"
+	dnsRRLIPv4PrefixEnvKey = "ISUCON13_DNS_RRL_IPV4_PREFIX_LEN"
+	dnsRRLIPv6PrefixEnvKey = "ISUCON13_DNS_RRL_IPV6_PREFIX_LEN"
+	dnsRRLWhitelistEnvKey  = "ISUCON13_DNS_RRL_WHITELIST"
+
+	dnsAppAddressesEnvKey        = "ISUCON13_DNS_APP_ADDRESSES"
+	dnsHealthCheckIntervalEnvKey = "ISUCON13_DNS_HEALTH_CHECK_INTERVAL"
+	dnsHealthCheckPortEnvKey     = "ISUCON13_DNS_HEALTH_CHECK_PORT"
+
+	dnsTransferAllowEnvKey     = "ISUCON13_DNS_TRANSFER_ALLOW"
+	powerDNSBackendAllowEnvKey = "ISUCON13_POWERDNS_BACKEND_ALLOW"
+
+	dnsEDNSUDPSizeEnvKey = "ISUCON13_DNS_EDNS_UDP_SIZE"
+	dnsDNSSECKeysEnvKey  = "ISUCON13_DNS_DNSSEC_KEYS"
+
+	dnsQueryLogEnvKey       = "ISUCON13_DNS_QUERY_LOG"
+	dnsQueryLogFileEnvKey   = "ISUCON13_DNS_QUERY_LOG_FILE"
+	dnsQueryLogFormatEnvKey = "ISUCON13_DNS_QUERY_LOG_FORMAT"
+)
+
+// 存在しない名前に対する応答方針
+type dnsNegativePolicy string
+
+const (
+	// 何も返さない(水責め対策の従来の挙動)
+	dnsNegativeDrop dnsNegativePolicy = "drop"
+	// NXDOMAINとSOAを返して、リゾルバにネガティブキャッシュさせる
+	dnsNegativeNXDomain dnsNegativePolicy = "nxdomain"
+	// REFUSEDを返す
+	dnsNegativeRefused dnsNegativePolicy = "refused"
+)
+
+// 組み込みDNSサーバの設定
+// サーバ構成が変わっても同じバイナリで動くように、環境変数で上書きできるようにしている
+type dnsConfig struct {
+	// ゾーンの頂点 (例: t.isucon.pw.)
+	Zone string
+	// ゾーンのNSレコードとグルーレコード
+	Nameservers []dnsNameserver
+	// サブドメインのAレコードで返すAPPサーバ
+	AppServers []dnsAppServer
+	// APPサーバのヘルスチェックの間隔(0なら無効)とポート
+	HealthCheckInterval time.Duration
+	HealthCheckPort     int
+	AnswerTTL           uint32
+	NSTTL               uint32
+	// SOAのminimum(ネガティブキャッシュのTTL)
+	NegativeTTL uint32
+	// SOAのRNAME (例: hostmaster.t.isucon.pw.)
+	SOAMbox string
+	// 存在しない名前に対する応答方針
+	NegativePolicy dnsNegativePolicy
+	// Response Rate Limiting (ResponsesPerSecondが0なら無効)
+	RRL rrlConfig
+	// ゾーン転送(AXFR/IXFR)を許可するセカンダリ(空なら全て拒否)
+	TransferAllow []netip.Prefix
+	// PowerDNSのremote backend APIを許可する送信元(空なら無効)
+	PowerDNSBackendAllow []netip.Prefix
+	// EDNS0で受け付けるUDPの最大サイズ
+	EDNSUDPSize uint16
+	// DNSSECの鍵ファイル(拡張子を除いたパス, 空ならDNSSECは無効)
+	DNSSECKeys []string
+	// 起動時にクエリログを有効にするか(管理用APIから切り替えられる)
+	QueryLog bool
+	// クエリログの出力先(空なら標準出力, dnstapなら"unix:/path"でソケットにも送れる)
+	QueryLogFile string
+	// クエリログの形式(json, dnstap)
+	QueryLogFormat string
+}
+
+type dnsNameserver struct {
+	Name    string
+	Address string
+}
+
+var dnsConf = defaultDNSConfig()
+
+func defaultDNSConfig() *dnsConfig {
+	return &dnsConfig{
+		Zone: "t.isucon.pw.",
+		Nameservers: []dnsNameserver{
+			{Name: "ns1.t.isucon.pw.", Address: "192.168.0.11"},
+		},
+		AppServers: []dnsAppServer{
+			{Address: "192.168.0.12", Weight: 1},
+		},
+		HealthCheckInterval: 5 * time.Second,
+		HealthCheckPort:     8080,
+		AnswerTTL:           120,
+		NSTTL:               120,
+		NegativeTTL:         60,
+		SOAMbox:             "hostmaster.t.isucon.pw.",
+		NegativePolicy:      dnsNegativeDrop,
+		// DNS Flag Day 2020の推奨値
+		EDNSUDPSize: 1232,
+		RRL: rrlConfig{
+			Slip:          2,
+			IPv4PrefixLen: 24,
+			IPv6PrefixLen: 56,
+		},
+	}
+}
+
+// 環境変数からDNSの設定を読み込む
+// answerAddressが空でなければ、Aレコードの応答先として使う(ISUCON13_DNS_APP_ADDRESSESがあればそちらを優先)
+//
+// ISUCON13_DNS_ZONE=t.isucon.pw.
+// ISUCON13_DNS_NAMESERVERS=ns1=192.168.0.11,ns2=192.168.0.13 (ゾーン外の名前はFQDNで書く)
+// ISUCON13_DNS_APP_ADDRESSES=192.168.0.12=2,192.168.0.11=1 (重みは省略すると1)
+// ISUCON13_DNS_HEALTH_CHECK_INTERVAL=5s (0なら無効)
+// ISUCON13_DNS_HEALTH_CHECK_PORT=8080
+// ISUCON13_DNS_ANSWER_TTL=120
+// ISUCON13_DNS_NS_TTL=120
+// ISUCON13_DNS_NEGATIVE_TTL=60
+// ISUCON13_DNS_SOA_MBOX=hostmaster.t.isucon.pw.
+// ISUCON13_DNS_NEGATIVE_POLICY=drop|nxdomain|refused
+// ISUCON13_DNS_RRL_RESPONSES_PER_SECOND=0 (0ならRRLは無効)
+// ISUCON13_DNS_RRL_ERRORS_PER_SECOND=0
+// ISUCON13_DNS_RRL_SLIP=2
+// ISUCON13_DNS_RRL_IPV4_PREFIX_LEN=24
+// ISUCON13_DNS_RRL_IPV6_PREFIX_LEN=56
+// ISUCON13_DNS_RRL_WHITELIST=127.0.0.0/8,192.168.0.0/24
+// ISUCON13_DNS_TRANSFER_ALLOW=192.168.0.13/32
+// ISUCON13_POWERDNS_BACKEND_ALLOW=192.168.0.13
+// ISUCON13_DNS_EDNS_UDP_SIZE=1232
+// ISUCON13_DNS_DNSSEC_KEYS=/home/isucon/dnssec/Kt.isucon.pw.+013+12345 (カンマ区切り)
+// ISUCON13_DNS_QUERY_LOG=false
+// ISUCON13_DNS_QUERY_LOG_FILE=/var/log/isupipe/dns-query.log
+func loadDNSConfig(answerAddress string) (*dnsConfig, error) {
+	conf := defaultDNSConfig()
+
+	if v, ok := os.LookupEnv(dnsZoneEnvKey); ok {
+		if _, ok := dns.IsDomainName(v); !ok {
+			return nil, fmt.Errorf("environment variable '%s' is not a domain name: %s", dnsZoneEnvKey, v)
+		}
+		conf.Zone = dns.CanonicalName(v)
+		conf.SOAMbox = "hostmaster." + conf.Zone
+		// ゾーンを変えた場合、デフォルトのネームサーバもそのゾーンに合わせる
+		for i := range conf.Nameservers {
+			conf.Nameservers[i].Name = "ns" + strconv.Itoa(i+1) + "." + conf.Zone
+		}
+	}
+	if v, ok := os.LookupEnv(dnsNameserversEnvKey); ok {
+		nameservers, err := parseDNSNameservers(v, conf.Zone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse environment variable '%s': %w", dnsNameserversEnvKey, err)
+		}
+		conf.Nameservers = nameservers
+	}
+	if answerAddress != "" {
+		if net.ParseIP(answerAddress).To4() == nil {
+			return nil, fmt.Errorf("answer address is not an IPv4 address: %s", answerAddress)
+		}
+		conf.AppServers = []dnsAppServer{{Address: answerAddress, Weight: 1}}
+	}
+	if v, ok := os.LookupEnv(dnsAppAddressesEnvKey); ok {
+		servers, err := parseDNSAppServers(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse environment variable '%s': %w", dnsAppAddressesEnvKey, err)
+		}
+		conf.AppServers = servers
+	}
+	if v, ok := os.LookupEnv(dnsHealthCheckIntervalEnvKey); ok {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval < 0 {
+			return nil, fmt.Errorf("failed to parse environment variable '%s' as duration: %s", dnsHealthCheckIntervalEnvKey, v)
+		}
+		conf.HealthCheckInterval = interval
+	}
+	if v, ok := os.LookupEnv(dnsHealthCheckPortEnvKey); ok {
+		port, err := strconv.Atoi(v)
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("failed to parse environment variable '%s' as port: %s", dnsHealthCheckPortEnvKey, v)
+		}
+		conf.HealthCheckPort = port
+	}
+	if v, ok := os.LookupEnv(dnsAnswerTTLEnvKey); ok {
+		ttl, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse environment variable '%s' as uint32: %+v", dnsAnswerTTLEnvKey, err)
+		}
+		conf.AnswerTTL = uint32(ttl)
+	}
+	if v, ok := os.LookupEnv(dnsNSTTLEnvKey); ok {
+		ttl, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse environment variable '%s' as uint32: %+v", dnsNSTTLEnvKey, err)
+		}
+		conf.NSTTL = uint32(ttl)
+	}
+
+	if v, ok := os.LookupEnv(dnsNegativeTTLEnvKey); ok {
+		ttl, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse environment variable '%s' as uint32: %+v", dnsNegativeTTLEnvKey, err)
+		}
+		conf.NegativeTTL = uint32(ttl)
+	}
+	if v, ok := os.LookupEnv(dnsSOAMboxEnvKey); ok {
+		if _, ok := dns.IsDomainName(v); !ok {
+			return nil, fmt.Errorf("environment variable '%s' is not a domain name: %s", dnsSOAMboxEnvKey, v)
+		}
+		conf.SOAMbox = dns.Fqdn(v)
+	}
+	if v, ok := os.LookupEnv(dnsNegativeEnvKey); ok {
+		switch policy := dnsNegativePolicy(strings.ToLower(v)); policy {
+		case dnsNegativeDrop, dnsNegativeNXDomain, dnsNegativeRefused:
+			conf.NegativePolicy = policy
+		default:
+			return nil, fmt.Errorf("environment variable '%s' must be one of drop, nxdomain, refused: %s", dnsNegativeEnvKey, v)
+		}
+	}
+
+	for key, dst := range map[string]*int{
+		dnsRRLResponsesEnvKey:  &conf.RRL.ResponsesPerSecond,
+		dnsRRLErrorsEnvKey:     &conf.RRL.ErrorsPerSecond,
+		dnsRRLSlipEnvKey:       &conf.RRL.Slip,
+		dnsRRLIPv4PrefixEnvKey: &conf.RRL.IPv4PrefixLen,
+		dnsRRLIPv6PrefixEnvKey: &conf.RRL.IPv6PrefixLen,
+	} {
+		if v, ok := os.LookupEnv(key); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("failed to parse environment variable '%s' as non-negative int: %s", key, v)
+			}
+			*dst = n
+		}
+	}
+	if conf.RRL.IPv4PrefixLen > 32 || conf.RRL.IPv6PrefixLen > 128 {
+		return nil, fmt.Errorf("rrl prefix length is out of range: /%d, /%d", conf.RRL.IPv4PrefixLen, conf.RRL.IPv6PrefixLen)
+	}
+	if v, ok := os.LookupEnv(dnsRRLWhitelistEnvKey); ok {
+		prefixes, err := parsePrefixes(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse environment variable '%s': %+v", dnsRRLWhitelistEnvKey, err)
+		}
+		conf.RRL.Whitelist = prefixes
+	}
+	if v, ok := os.LookupEnv(dnsTransferAllowEnvKey); ok {
+		prefixes, err := parsePrefixes(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse environment variable '%s': %+v", dnsTransferAllowEnvKey, err)
+		}
+		conf.TransferAllow = prefixes
+	}
+	if v, ok := os.LookupEnv(powerDNSBackendAllowEnvKey); ok {
+		prefixes, err := parsePrefixes(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse environment variable '%s': %+v", powerDNSBackendAllowEnvKey, err)
+		}
+		conf.PowerDNSBackendAllow = prefixes
+	}
+	if v, ok := os.LookupEnv(dnsEDNSUDPSizeEnvKey); ok {
+		size, err := strconv.ParseUint(v, 10, 16)
+		if err != nil || size < dns.MinMsgSize {
+			return nil, fmt.Errorf("failed to parse environment variable '%s' as udp size (>= %d): %s", dnsEDNSUDPSizeEnvKey, dns.MinMsgSize, v)
+		}
+		conf.EDNSUDPSize = uint16(size)
+	}
+	if v, ok := os.LookupEnv(dnsDNSSECKeysEnvKey); ok {
+		for _, path := range strings.Split(v, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				conf.DNSSECKeys = append(conf.DNSSECKeys, path)
+			}
+		}
+	}
+	if v, ok := os.LookupEnv(dnsQueryLogEnvKey); ok {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse environment variable '%s' as bool: %+v", dnsQueryLogEnvKey, err)
+		}
+		conf.QueryLog = enabled
+	}
+	if v, ok := os.LookupEnv(dnsQueryLogFileEnvKey); ok {
+		conf.QueryLogFile = v
+	}
+	if v, ok := os.LookupEnv(dnsQueryLogFormatEnvKey); ok {
+		if v != dnsQueryLogJSON && v != dnsQueryLogDNSTap {
+			return nil, fmt.Errorf("environment variable '%s' must be %s or %s", dnsQueryLogFormatEnvKey, dnsQueryLogJSON, dnsQueryLogDNSTap)
+		}
+		conf.QueryLogFormat = v
+	}
+
+	return conf, nil
+}
+
+// "ns1=192.168.0.11,ns2.example.com.=192.168.0.13" の形式
+// 末尾にドットのない名前はゾーンからの相対名とみなす
+func parseDNSNameservers(s, zone string) ([]dnsNameserver, error) {
+	nameservers := []dnsNameserver{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, addr, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("nameserver must be name=address: %s", entry)
+		}
+		if !dns.IsFqdn(name) {
+			name = name + "." + zone
+		}
+		if _, ok := dns.IsDomainName(name); !ok {
+			return nil, fmt.Errorf("invalid nameserver name: %s", name)
+		}
+		if net.ParseIP(addr).To4() == nil {
+			return nil, fmt.Errorf("nameserver address is not an IPv4 address: %s", addr)
+		}
+		nameservers = append(nameservers, dnsNameserver{Name: dns.CanonicalName(name), Address: addr})
+	}
+	if len(nameservers) == 0 {
+		return nil, fmt.Errorf("at least one nameserver is required")
+	}
+	return nameservers, nil
+}
+
+// "192.168.0.12=2,192.168.0.11" の形式
+// 重みを省略した場合は1
+func parseDNSAppServers(s string) ([]dnsAppServer, error) {
+	servers := []dnsAppServer{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		addr, weight, ok := strings.Cut(entry, "=")
+		server := dnsAppServer{Address: addr, Weight: 1}
+		if ok {
+			w, err := strconv.Atoi(weight)
+			if err != nil || w <= 0 {
+				return nil, fmt.Errorf("app server weight must be positive int: %s", entry)
+			}
+			server.Weight = w
+		}
+		if net.ParseIP(server.Address).To4() == nil {
+			return nil, fmt.Errorf("app server address is not an IPv4 address: %s", server.Address)
+		}
+		servers = append(servers, server)
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("at least one app server is required")
+	}
+	return servers, nil
+}
+
+// "127.0.0.0/8,192.168.0.13" の形式
+// プレフィックス長が無い場合はそのアドレスだけを表す
+func parsePrefixes(s string) ([]netip.Prefix, error) {
+	prefixes := []netip.Prefix{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"crypto"
+	"encoding/base32"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// オンラインDNSSEC署名
+// ユーザ登録のたびにゾーンが変わるので、事前署名ではなく応答するときに署名する
+// 存在しない名前はNSEC3のwhite lies(RFC 7129)で否定するので、ゾーンの全ての名前を列挙されることはない
+// ゾーン転送(AXFR/IXFR)は署名しない
+
+const (
+	// 署名の有効期間
+	rrsigValidity = 7 * 24 * time.Hour
+	// 時計のずれを考慮して、少し前から有効にする
+	rrsigInceptionSkew = time.Hour
+	// 有効期間の残りがこれを切ったら署名し直す
+	rrsigRefresh = 6 * 24 * time.Hour
+	// 署名のキャッシュの上限(超えたら捨てる)
+	maxRRSIGCache = 100000
+)
+
+type dnssecKey struct {
+	dnskey *dns.DNSKEY
+	signer crypto.Signer
+}
+
+type dnssecSigner struct {
+	// DNSKEYに署名する鍵(KSK)
+	ksks []dnssecKey
+	// その他に署名する鍵(ZSK)
+	zsks []dnssecKey
+	now  func() time.Time
+
+	mu    sync.Mutex
+	cache map[string][]dns.RR
+}
+
+// nilの場合はDNSSECが無効
+var dnsSigner *dnssecSigner
+
+// BINDのdnssec-keygenと同じ形式の鍵を読み込む
+// prefixは "Kt.isucon.pw.+013+12345" のような拡張子を除いたパスで、.keyと.privateを読む
+// SEPフラグ(257)の鍵はKSK、それ以外はZSKとして使う。鍵が1つだけならその鍵で全て署名する
+func loadDNSSECKeys(prefixes []string) (*dnssecSigner, error) {
+	s := &dnssecSigner{now: time.Now, cache: map[string][]dns.RR{}}
+	for _, prefix := range prefixes {
+		key, err := loadDNSSECKey(prefix)
+		if err != nil {
+			return nil, err
+		}
+		if key.dnskey.Flags&dns.SEP != 0 {
+			s.ksks = append(s.ksks, key)
+		} else {
+			s.zsks = append(s.zsks, key)
+		}
+	}
+	switch {
+	case len(s.ksks) == 0 && len(s.zsks) == 0:
+		return nil, fmt.Errorf("no dnssec keys")
+	case len(s.ksks) == 0:
+		s.ksks = s.zsks
+	case len(s.zsks) == 0:
+		s.zsks = s.ksks
+	}
+	return s, nil
+}
+
+func loadDNSSECKey(prefix string) (dnssecKey, error) {
+	pub, err := os.Open(prefix + ".key")
+	if err != nil {
+		return dnssecKey{}, err
+	}
+	defer pub.Close()
+	rr, err := dns.ReadRR(pub, prefix+".key")
+	if err != nil {
+		return dnssecKey{}, fmt.Errorf("failed to read %s.key: %w", prefix, err)
+	}
+	dnskey, ok := rr.(*dns.DNSKEY)
+	if !ok {
+		return dnssecKey{}, fmt.Errorf("%s.key is not a DNSKEY record", prefix)
+	}
+	if !strings.EqualFold(dnskey.Hdr.Name, dnsConf.Zone) {
+		return dnssecKey{}, fmt.Errorf("%s.key is not for zone %s: %s", prefix, dnsConf.Zone, dnskey.Hdr.Name)
+	}
+	dnskey.Hdr.Name = dnsConf.Zone
+	dnskey.Hdr.Ttl = dnsConf.NSTTL
+
+	priv, err := os.Open(prefix + ".private")
+	if err != nil {
+		return dnssecKey{}, err
+	}
+	defer priv.Close()
+	privkey, err := dnskey.ReadPrivateKey(priv, prefix+".private")
+	if err != nil {
+		return dnssecKey{}, fmt.Errorf("failed to read %s.private: %w", prefix, err)
+	}
+	signer, ok := privkey.(crypto.Signer)
+	if !ok {
+		return dnssecKey{}, fmt.Errorf("%s.private is not a signing key", prefix)
+	}
+	return dnssecKey{dnskey: dnskey, signer: signer}, nil
+}
+
+// ゾーン頂点のDNSKEY
+func (s *dnssecSigner) dnskeys() []dns.RR {
+	rrs := []dns.RR{}
+	for _, keys := range [][]dnssecKey{s.ksks, s.zsks} {
+		for _, key := range keys {
+			if !slices.ContainsFunc(rrs, func(rr dns.RR) bool { return rr == dns.RR(key.dnskey) }) {
+				rrs = append(rrs, key.dnskey)
+			}
+		}
+	}
+	return rrs
+}
+
+// ゾーン頂点のNSEC3PARAM
+// RFC 9276に従い、繰り返し0回・ソルト無し
+func nsec3ParamRR() dns.RR {
+	return &dns.NSEC3PARAM{
+		Hdr:  dns.RR_Header{Name: dnsConf.Zone, Rrtype: dns.TypeNSEC3PARAM, Class: dns.ClassINET, Ttl: dnsConf.NSTTL},
+		Hash: dns.SHA1,
+	}
+}
+
+// rrsetに対するRRSIG
+// 同じrrsetの署名はしばらく使い回す
+func (s *dnssecSigner) sign(rrset []dns.RR) ([]dns.RR, error) {
+	keys := s.zsks
+	if rrset[0].Header().Rrtype == dns.TypeDNSKEY {
+		keys = s.ksks
+	}
+
+	lines := make([]string, len(rrset))
+	for i, rr := range rrset {
+		lines[i] = rr.String()
+	}
+	slices.Sort(lines)
+	cacheKey := strings.Join(lines, "\n")
+
+	now := s.now()
+	s.mu.Lock()
+	sigs, ok := s.cache[cacheKey]
+	s.mu.Unlock()
+	if ok && time.Unix(int64(sigs[0].(*dns.RRSIG).Expiration), 0).Sub(now) > rrsigRefresh {
+		return sigs, nil
+	}
+
+	h := rrset[0].Header()
+	sigs = make([]dns.RR, 0, len(keys))
+	for _, key := range keys {
+		sig := &dns.RRSIG{
+			Hdr:        dns.RR_Header{Ttl: h.Ttl},
+			Algorithm:  key.dnskey.Algorithm,
+			Inception:  uint32(now.Add(-rrsigInceptionSkew).Unix()),
+			Expiration: uint32(now.Add(rrsigValidity).Unix()),
+			KeyTag:     key.dnskey.KeyTag(),
+			SignerName: dnsConf.Zone,
+		}
+		if err := sig.Sign(key.signer, rrset); err != nil {
+			return nil, err
+		}
+		sigs = append(sigs, sig)
+	}
+
+	s.mu.Lock()
+	if len(s.cache) >= maxRRSIGCache {
+		s.cache = map[string][]dns.RR{}
+	}
+	s.cache[cacheKey] = sigs
+	s.mu.Unlock()
+	return sigs, nil
+}
+
+// セクションの中のrrsetごとに署名を付ける
+func (s *dnssecSigner) signSection(rrs []dns.RR) ([]dns.RR, error) {
+	type rrsetKey struct {
+		name   string
+		rrtype uint16
+	}
+	order := []rrsetKey{}
+	rrsets := map[rrsetKey][]dns.RR{}
+	for _, rr := range rrs {
+		h := rr.Header()
+		if h.Rrtype == dns.TypeOPT || h.Rrtype == dns.TypeRRSIG {
+			continue
+		}
+		key := rrsetKey{name: strings.ToLower(h.Name), rrtype: h.Rrtype}
+		if _, ok := rrsets[key]; !ok {
+			order = append(order, key)
+		}
+		rrsets[key] = append(rrsets[key], rr)
+	}
+
+	signed := slices.Clone(rrs)
+	for _, key := range order {
+		// ゾーン外のレコードには署名しない
+		if !dns.IsSubDomain(dnsConf.Zone, key.name) {
+			continue
+		}
+		sigs, err := s.sign(rrsets[key])
+		if err != nil {
+			return nil, err
+		}
+		signed = append(signed, sigs...)
+	}
+	return signed, nil
+}
+
+// 応答に署名して、否定応答にはNSEC3を付ける
+func (s *dnssecSigner) signMsg(m *dns.Msg) error {
+	if len(m.Question) == 1 {
+		// CNAMEを辿った先の名前が否定の対象
+		name := m.Question[0].Name
+		for _, rr := range m.Answer {
+			if cname, ok := rr.(*dns.CNAME); ok && strings.EqualFold(cname.Hdr.Name, name) {
+				name = cname.Target
+			}
+		}
+		negative := len(m.Ns) > 0 && m.Ns[0].Header().Rrtype == dns.TypeSOA
+		switch {
+		case m.Rcode == dns.RcodeNameError:
+			m.Ns = append(m.Ns, nxdomainProof(name)...)
+		case m.Rcode == dns.RcodeSuccess && negative:
+			m.Ns = append(m.Ns, nodataProof(name))
+		}
+	}
+
+	var err error
+	if m.Answer, err = s.signSection(m.Answer); err != nil {
+		return err
+	}
+	if m.Ns, err = s.signSection(m.Ns); err != nil {
+		return err
+	}
+	if m.Extra, err = s.signSection(m.Extra); err != nil {
+		return err
+	}
+	return nil
+}
+
+// NSEC3のハッシュ(大文字のbase32hex)
+func nsec3Hash(name string) string {
+	return dns.HashName(name, dns.SHA1, 0, "")
+}
+
+// ハッシュに1を足す(deltaが-1なら引く)
+func nsec3HashAdd(hash string, delta int) string {
+	b, err := base32.HexEncoding.WithPadding(base32.NoPadding).DecodeString(hash)
+	if err != nil {
+		return hash
+	}
+	for i := len(b) - 1; i >= 0; i-- {
+		if delta > 0 {
+			b[i]++
+			if b[i] != 0 {
+				break
+			}
+		} else {
+			b[i]--
+			if b[i] != 0xff {
+				break
+			}
+		}
+	}
+	return base32.HexEncoding.WithPadding(base32.NoPadding).EncodeToString(b)
+}
+
+func newNSEC3(ownerHash, nextHash string, types []uint16) dns.RR {
+	return &dns.NSEC3{
+		Hdr: dns.RR_Header{
+			Name:   strings.ToLower(ownerHash) + "." + dnsConf.Zone,
+			Rrtype: dns.TypeNSEC3,
+			Class:  dns.ClassINET,
+			Ttl:    dnsConf.NegativeTTL,
+		},
+		Hash:       dns.SHA1,
+		HashLength: 20,
+		NextDomain: nextHash,
+		TypeBitMap: types,
+	}
+}
+
+// nameに一致するNSEC3 (nameに存在する型を列挙する)
+func matchingNSEC3(name string) dns.RR {
+	hash := nsec3Hash(name)
+	return newNSEC3(hash, nsec3HashAdd(hash, 1), nodeTypes(name))
+}
+
+// nameを覆うNSEC3 (nameのハッシュの前後1だけを覆う)
+func coveringNSEC3(name string) dns.RR {
+	hash := nsec3Hash(name)
+	return newNSEC3(nsec3HashAdd(hash, -1), nsec3HashAdd(hash, 1), nil)
+}
+
+// nameに存在する型(NSEC3のタイプビットマップ)
+func nodeTypes(name string) []uint16 {
+	types := []uint16{dns.TypeRRSIG}
+	node := lookupNode(name)
+	if node == nil {
+		return types
+	}
+	if node.app {
+		types = append(types, dns.TypeA)
+	}
+	if strings.EqualFold(name, dnsConf.Zone) {
+		types = append(types, dns.TypeNS, dns.TypeSOA, dns.TypeDNSKEY, dns.TypeNSEC3PARAM)
+	}
+	for rrtype := range node.rrsets {
+		if !slices.Contains(types, rrtype) {
+			types = append(types, rrtype)
+		}
+	}
+	slices.Sort(types)
+	return types
+}
+
+// NODATAの証明: 名前は存在するが、その型は無い
+func nodataProof(name string) dns.RR {
+	return matchingNSEC3(name)
+}
+
+// NXDOMAINの証明(RFC 5155 7.2.2)
+// 最も近い祖先(closest encloser)に一致するNSEC3、その1つ下の名前(next closer)を覆うNSEC3、
+// closest encloserのワイルドカードを覆うNSEC3
+func nxdomainProof(name string) []dns.RR {
+	name = dns.CanonicalName(name)
+	labels := dns.SplitDomainName(name)
+	nextCloser := name
+	encloser := dnsConf.Zone
+	for i := 1; i < len(labels); i++ {
+		candidate := dns.Fqdn(strings.Join(labels[i:], "."))
+		if !dns.IsSubDomain(dnsConf.Zone, candidate) {
+			break
+		}
+		if lookupNode(candidate) != nil {
+			encloser = candidate
+			nextCloser = dns.Fqdn(strings.Join(labels[i-1:], "."))
+			break
+		}
+	}
+	return []dns.RR{
+		matchingNSEC3(encloser),
+		coveringNSEC3(nextCloser),
+		coveringNSEC3("*." + encloser),
+	}
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// テスト用の鍵を作ってディスクに書き出し、loadDNSSECKeysで読み込む
+func setupDNSSEC(t *testing.T) *dns.DNSKEY {
+	t.Helper()
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: dnsConf.Zone, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     dns.ZONE | dns.SEP,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	priv, err := key.Generate(256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := filepath.Join(t.TempDir(), fmt.Sprintf("K%s+%03d+%05d", dnsConf.Zone, key.Algorithm, key.KeyTag()))
+	if err := os.WriteFile(prefix+".key", []byte(key.String()+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(prefix+".private", []byte(key.PrivateKeyString(priv)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := loadDNSSECKeys([]string{prefix})
+	if err != nil {
+		t.Fatalf("failed to load dnssec keys: %+v", err)
+	}
+	dnsSigner = signer
+	t.Cleanup(func() { dnsSigner = nil })
+	return signer.dnskeys()[0].(*dns.DNSKEY)
+}
+
+// EDNS0付きのクエリを投げる
+func queryEDNS(name string, qtype uint16, udpSize uint16, do bool) *dns.Msg {
+	r := new(dns.Msg)
+	r.SetQuestion(name, qtype)
+	r.SetEdns0(udpSize, do)
+	w := &testResponseWriter{}
+	handle(w, r)
+	return w.msg
+}
+
+// セクション内の全てのRRSIGを検証して、署名されたrrsetの型を返す
+func verifySection(t *testing.T, key *dns.DNSKEY, rrs []dns.RR) []uint16 {
+	t.Helper()
+	covered := []uint16{}
+	for _, rr := range rrs {
+		sig, ok := rr.(*dns.RRSIG)
+		if !ok {
+			continue
+		}
+		rrset := []dns.RR{}
+		for _, r := range rrs {
+			if r.Header().Rrtype == sig.TypeCovered && strings.EqualFold(r.Header().Name, sig.Hdr.Name) {
+				rrset = append(rrset, r)
+			}
+		}
+		if err := sig.Verify(key, rrset); err != nil {
+			t.Errorf("failed to verify RRSIG for %s/%s: %+v", sig.Hdr.Name, dns.TypeToString[sig.TypeCovered], err)
+		}
+		if !sig.ValidityPeriod(time.Now()) {
+			t.Errorf("RRSIG for %s/%s is not valid now", sig.Hdr.Name, dns.TypeToString[sig.TypeCovered])
+		}
+		covered = append(covered, sig.TypeCovered)
+	}
+	return covered
+}
+
+func nsec3s(rrs []dns.RR) []*dns.NSEC3 {
+	list := []*dns.NSEC3{}
+	for _, rr := range rrs {
+		if n, ok := rr.(*dns.NSEC3); ok {
+			list = append(list, n)
+		}
+	}
+	return list
+}
+
+func TestDNSSEC(t *testing.T) {
+	t.Cleanup(func() { dnsConf = defaultDNSConfig() })
+	dnsConf = defaultDNSConfig()
+	dnsConf.NegativePolicy = dnsNegativeNXDomain
+	resetSubdomains()
+	key := setupDNSSEC(t)
+
+	t.Run("Aレコードに署名する", func(t *testing.T) {
+		m := queryEDNS("pipe.t.isucon.pw.", dns.TypeA, 4096, true)
+		if covered := verifySection(t, key, m.Answer); !slices.Equal(covered, []uint16{dns.TypeA}) {
+			t.Errorf("covered = %v, want [A]", covered)
+		}
+	})
+
+	t.Run("DNSKEYに署名する", func(t *testing.T) {
+		m := queryEDNS("t.isucon.pw.", dns.TypeDNSKEY, 4096, true)
+		if covered := verifySection(t, key, m.Answer); !slices.Equal(covered, []uint16{dns.TypeDNSKEY}) {
+			t.Errorf("covered = %v, want [DNSKEY]", covered)
+		}
+	})
+
+	t.Run("DOビットが無ければ署名しない", func(t *testing.T) {
+		m := queryEDNS("pipe.t.isucon.pw.", dns.TypeA, 4096, false)
+		if covered := verifySection(t, key, m.Answer); len(covered) != 0 {
+			t.Errorf("covered = %v, want none", covered)
+		}
+	})
+
+	t.Run("NXDOMAINはNSEC3で否定する", func(t *testing.T) {
+		qname := "water-torture.t.isucon.pw."
+		m := queryEDNS(qname, dns.TypeA, 4096, true)
+		if m.Rcode != dns.RcodeNameError {
+			t.Fatalf("rcode = %s, want NXDOMAIN", dns.RcodeToString[m.Rcode])
+		}
+		covered := verifySection(t, key, m.Ns)
+		if !slices.Contains(covered, dns.TypeSOA) || !slices.Contains(covered, dns.TypeNSEC3) {
+			t.Errorf("covered = %v, want SOA and NSEC3", covered)
+		}
+		// closest encloser proof (RFC 5155 8.4)
+		list := nsec3s(m.Ns)
+		if !slices.ContainsFunc(list, func(n *dns.NSEC3) bool { return n.Match(dnsConf.Zone) }) {
+			t.Errorf("no NSEC3 matches the closest encloser")
+		}
+		if !slices.ContainsFunc(list, func(n *dns.NSEC3) bool { return n.Cover(qname) }) {
+			t.Errorf("no NSEC3 covers the next closer name")
+		}
+		if !slices.ContainsFunc(list, func(n *dns.NSEC3) bool { return n.Cover("*." + dnsConf.Zone) }) {
+			t.Errorf("no NSEC3 covers the wildcard")
+		}
+		// white lies: 他の名前は覆わない
+		if slices.ContainsFunc(list, func(n *dns.NSEC3) bool { return n.Cover("pipe.t.isucon.pw.") }) {
+			t.Errorf("NSEC3 covers an existing name")
+		}
+	})
+
+	t.Run("NODATAはNSEC3で型が無いことを示す", func(t *testing.T) {
+		m := queryEDNS("pipe.t.isucon.pw.", dns.TypeAAAA, 4096, true)
+		if m.Rcode != dns.RcodeSuccess || len(m.Answer) != 0 {
+			t.Fatalf("rcode = %s, answer = %v, want NODATA", dns.RcodeToString[m.Rcode], m.Answer)
+		}
+		verifySection(t, key, m.Ns)
+		list := nsec3s(m.Ns)
+		if len(list) != 1 || !list[0].Match("pipe.t.isucon.pw.") {
+			t.Fatalf("nsec3 = %v, want one matching pipe.t.isucon.pw.", list)
+		}
+		if slices.Contains(list[0].TypeBitMap, dns.TypeAAAA) || !slices.Contains(list[0].TypeBitMap, dns.TypeA) {
+			t.Errorf("type bitmap = %v, want A without AAAA", list[0].TypeBitMap)
+		}
+	})
+}
+
+func TestEDNS0(t *testing.T) {
+	t.Cleanup(resetSubdomains)
+	resetSubdomains()
+	// 512バイトに収まらない応答を作る
+	for i := 0; i < 12; i++ {
+		rr := mustNewRR(fmt.Sprintf("big.t.isucon.pw. 60 IN TXT \"%02d%s\"", i, strings.Repeat("x", 60)))
+		if err := addRecord(rr); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	packedLen := func(t *testing.T, m *dns.Msg) int {
+		t.Helper()
+		buf, err := m.Pack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(buf)
+	}
+
+	t.Run("EDNS0が無ければ512バイトに切り詰めてTC=1", func(t *testing.T) {
+		m := query("big.t.isucon.pw.", dns.TypeTXT)
+		if !m.Truncated || packedLen(t, m) > dns.MinMsgSize {
+			t.Errorf("truncated = %v, len = %d", m.Truncated, packedLen(t, m))
+		}
+		if m.IsEdns0() != nil {
+			t.Errorf("OPT should not be in the response to a non-EDNS query")
+		}
+	})
+
+	t.Run("EDNS0のUDPサイズに収まれば切り詰めない", func(t *testing.T) {
+		m := queryEDNS("big.t.isucon.pw.", dns.TypeTXT, 1232, false)
+		if m.Truncated || len(m.Answer) != 12 {
+			t.Errorf("truncated = %v, answers = %d", m.Truncated, len(m.Answer))
+		}
+		opt := m.IsEdns0()
+		if opt == nil || opt.UDPSize() != dnsConf.EDNSUDPSize {
+			t.Errorf("OPT = %v, want udp size %d", opt, dnsConf.EDNSUDPSize)
+		}
+	})
+
+	t.Run("TCPなら切り詰めない", func(t *testing.T) {
+		r := new(dns.Msg)
+		r.SetQuestion("big.t.isucon.pw.", dns.TypeTXT)
+		w := &testTransferWriter{remote: &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 53}}
+		handle(w, r)
+		if len(w.msgs) != 1 || w.msgs[0].Truncated || len(w.msgs[0].Answer) != 12 {
+			t.Errorf("response = %v", w.msgs)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"log"
+	"net"
+
+	"github.com/miekg/dns"
+)
+
+// EDNS0と署名を反映して応答する
+// UDPの場合はクライアントが受け取れるサイズに収まるように切り詰めて、TC=1でTCPに誘導する
+func respond(w dns.ResponseWriter, r, m *dns.Msg) {
+	size := dns.MinMsgSize
+	if opt := r.IsEdns0(); opt != nil {
+		size = int(min(opt.UDPSize(), dnsConf.EDNSUDPSize))
+		if opt.Do() && dnsSigner != nil {
+			if err := dnsSigner.signMsg(m); err != nil {
+				log.Printf("failed to sign dns response: %+v", err)
+				m.Rcode = dns.RcodeServerFailure
+				m.Answer, m.Ns, m.Extra = nil, nil, nil
+			}
+		}
+		m.SetEdns0(dnsConf.EDNSUDPSize, opt.Do())
+	}
+	if _, ok := w.RemoteAddr().(*net.TCPAddr); ok {
+		size = dns.MaxMsgSize
+	}
+	// 512未満は512として扱われる
+	m.Truncate(size)
+	writeMsg(w, m)
+}
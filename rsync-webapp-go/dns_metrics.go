@@ -0,0 +1,297 @@
+package main
+
+import (
+	"cmp"
+	"container/heap"
+	"hash/maphash"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// 上位の名前を数えるために保持する名前の数
+	// 水責めでランダムな名前が大量に来てもメモリを食わないように、Space-Savingで近似する
+	dnsTopNamesCapacity = 1024
+	// 上位の名前を数える表の分割数(クエリごとのロックの競合を減らす)
+	dnsTopNamesShards = 16
+)
+
+// 応答時間のヒストグラムのバケット(上限, マイクロ秒)
+var dnsLatencyBucketsMicros = [...]int64{50, 100, 250, 500, 1000, 2500, 5000, 10000, 25000, 50000, 100000}
+
+// 応答時間を数えるプロトコル
+var dnsMetricsProtos = [...]string{"udp", "tcp"}
+
+type dnsLatencyHistogram struct {
+	// バケットごとの件数(最後はそれ以上)
+	counts    [len(dnsLatencyBucketsMicros) + 1]atomic.Int64
+	count     atomic.Int64
+	sumMicros atomic.Int64
+}
+
+// 値(qtype, rcode)ごとの件数
+// 普段来る値は配列で数えて、それ以外だけsync.Mapに入れる。どちらもロックを取らない
+type dnsCounters struct {
+	small [256]atomic.Int64
+	large sync.Map
+}
+
+func (c *dnsCounters) add(key int) {
+	if 0 <= key && key < len(c.small) {
+		c.small[key].Add(1)
+		return
+	}
+	v, ok := c.large.Load(key)
+	if !ok {
+		v, _ = c.large.LoadOrStore(key, new(atomic.Int64))
+	}
+	v.(*atomic.Int64).Add(1)
+}
+
+func (c *dnsCounters) each(f func(key int, n int64)) {
+	for key := range c.small {
+		if n := c.small[key].Load(); n > 0 {
+			f(key, n)
+		}
+	}
+	c.large.Range(func(key, v any) bool {
+		f(key.(int), v.(*atomic.Int64).Load())
+		return true
+	})
+}
+
+type dnsNameCount struct {
+	name  string
+	count int64
+	// heapの中の位置
+	index int
+}
+
+// 件数の少ない順のheap
+type dnsNameHeap []*dnsNameCount
+
+func (h dnsNameHeap) Len() int           { return len(h) }
+func (h dnsNameHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+func (h dnsNameHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+func (h *dnsNameHeap) Push(x any) {
+	n := x.(*dnsNameCount)
+	n.index = len(*h)
+	*h = append(*h, n)
+}
+func (h *dnsNameHeap) Pop() any {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// 上位の名前を数える表の1つ分
+type dnsTopNames struct {
+	mu    sync.Mutex
+	names map[string]*dnsNameCount
+	heap  dnsNameHeap
+}
+
+// Space-Saving: 満杯なら最小の名前を追い出して、その件数を引き継ぐ
+// 最小の名前はheapの先頭にあるので、追い出してもO(log N)で済む
+func (t *dnsTopNames) count(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if n, ok := t.names[name]; ok {
+		n.count++
+		heap.Fix(&t.heap, n.index)
+		return
+	}
+	if len(t.heap) < dnsTopNamesCapacity/dnsTopNamesShards {
+		n := &dnsNameCount{name: name, count: 1}
+		t.names[name] = n
+		heap.Push(&t.heap, n)
+		return
+	}
+	n := t.heap[0]
+	delete(t.names, n.name)
+	n.name = name
+	n.count++
+	t.names[name] = n
+	heap.Fix(&t.heap, 0)
+}
+
+type dnsMetrics struct {
+	queries  atomic.Int64
+	answered atomic.Int64
+	dropped  atomic.Int64
+
+	qtypes    dnsCounters
+	rcodes    dnsCounters
+	latencies [len(dnsMetricsProtos)]dnsLatencyHistogram
+
+	// 名前のハッシュで表を選ぶ(シードはランダムなので、狙って1つの表に集めることはできない)
+	seed  maphash.Seed
+	names [dnsTopNamesShards]dnsTopNames
+}
+
+type DNSMetricsResponse struct {
+	Queries  int64                     `json:"queries"`
+	Answered int64                     `json:"answered"`
+	Dropped  int64                     `json:"dropped"`
+	QTypes   map[string]int64          `json:"qtypes"`
+	Rcodes   map[string]int64          `json:"rcodes"`
+	TopNames []DNSNameCount            `json:"top_names"`
+	Latency  map[string]DNSLatencyData `json:"latency"`
+}
+
+type DNSNameCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type DNSLatencyData struct {
+	Count     int64              `json:"count"`
+	SumMicros int64              `json:"sum_us"`
+	Buckets   []DNSLatencyBucket `json:"buckets"`
+}
+
+type DNSLatencyBucket struct {
+	// 上限(マイクロ秒, 0なら上限なし)
+	LeMicros int64 `json:"le_us"`
+	// このバケットまでの累積件数
+	Count int64 `json:"count"`
+}
+
+var dnsStats = newDNSMetrics()
+
+func newDNSMetrics() *dnsMetrics {
+	s := &dnsMetrics{seed: maphash.MakeSeed()}
+	for i := range s.names {
+		s.names[i].names = map[string]*dnsNameCount{}
+	}
+	return s
+}
+
+// 1クエリ分を記録する
+// 応答しなかった場合はmがnil
+// 水責めの間も全てのクエリで呼ばれるので、名前を数える表の1つ以外ではロックを取らない
+func (s *dnsMetrics) observe(proto string, q dns.Question, m *dns.Msg, elapsed time.Duration) {
+	s.queries.Add(1)
+	if m == nil {
+		s.dropped.Add(1)
+	} else {
+		s.answered.Add(1)
+		s.rcodes.add(m.Rcode)
+	}
+	s.qtypes.add(int(q.Qtype))
+
+	name := strings.ToLower(q.Name)
+	s.names[maphash.String(s.seed, name)%dnsTopNamesShards].count(name)
+
+	if i := slices.Index(dnsMetricsProtos[:], proto); 0 <= i {
+		h := &s.latencies[i]
+		micros := elapsed.Microseconds()
+		b, _ := slices.BinarySearch(dnsLatencyBucketsMicros[:], micros)
+		h.counts[b].Add(1)
+		h.count.Add(1)
+		h.sumMicros.Add(micros)
+	}
+}
+
+func (s *dnsMetrics) snapshot(top int) DNSMetricsResponse {
+	res := DNSMetricsResponse{
+		Queries:  s.queries.Load(),
+		Answered: s.answered.Load(),
+		Dropped:  s.dropped.Load(),
+		QTypes:   map[string]int64{},
+		Rcodes:   map[string]int64{},
+		TopNames: []DNSNameCount{},
+		Latency:  map[string]DNSLatencyData{},
+	}
+
+	s.qtypes.each(func(qtype int, n int64) {
+		res.QTypes[dns.Type(qtype).String()] = n
+	})
+	s.rcodes.each(func(rcode int, n int64) {
+		res.Rcodes[dns.RcodeToString[rcode]] = n
+	})
+	for i := range s.names {
+		t := &s.names[i]
+		t.mu.Lock()
+		for _, n := range t.heap {
+			res.TopNames = append(res.TopNames, DNSNameCount{Name: n.name, Count: n.count})
+		}
+		t.mu.Unlock()
+	}
+	slices.SortFunc(res.TopNames, func(a, b DNSNameCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	if len(res.TopNames) > top {
+		res.TopNames = res.TopNames[:top]
+	}
+	for i, proto := range dnsMetricsProtos {
+		h := &s.latencies[i]
+		count := h.count.Load()
+		if count == 0 {
+			continue
+		}
+		// 記録中のクエリがあると合計と各バケットが少しずれることがあるが、メトリクスなので気にしない
+		data := DNSLatencyData{Count: count, SumMicros: h.sumMicros.Load()}
+		var cumulative int64
+		for b := range h.counts {
+			cumulative += h.counts[b].Load()
+			var le int64
+			if b < len(dnsLatencyBucketsMicros) {
+				le = dnsLatencyBucketsMicros[b]
+			}
+			data.Buckets = append(data.Buckets, DNSLatencyBucket{LeMicros: le, Count: cumulative})
+		}
+		res.Latency[proto] = data
+	}
+	return res
+}
+
+// 応答を記録するdns.ResponseWriter
+type metricsResponseWriter struct {
+	dns.ResponseWriter
+	msg *dns.Msg
+}
+
+func (w *metricsResponseWriter) WriteMsg(m *dns.Msg) error {
+	// ゾーン転送は複数回書くので、最初の応答だけ記録する
+	if w.msg == nil {
+		w.msg = m
+	}
+	return w.ResponseWriter.WriteMsg(m)
+}
+
+// メトリクスとクエリログを記録する
+// RRLで捨てた応答も「応答しなかった」として数えるため、withRRLの外側に挟むこと
+func withMetrics(next dns.HandlerFunc) dns.HandlerFunc {
+	return func(w dns.ResponseWriter, r *dns.Msg) {
+		start := time.Now()
+		mw := &metricsResponseWriter{ResponseWriter: w}
+		next(mw, r)
+		elapsed := time.Since(start)
+
+		if len(r.Question) == 0 {
+			return
+		}
+		proto := "udp"
+		if _, ok := w.RemoteAddr().(*net.TCPAddr); ok {
+			proto = "tcp"
+		}
+		dnsStats.observe(proto, r.Question[0], mw.msg, elapsed)
+		dnsQueryLog.write(w.RemoteAddr(), proto, r, mw.msg, start, elapsed)
+	}
+}
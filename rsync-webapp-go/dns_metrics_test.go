@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dnstap/golang-dnstap"
+	"github.com/miekg/dns"
+)
+
+func TestDNSMetrics(t *testing.T) {
+	originalStats, originalLog := dnsStats, dnsQueryLog
+	var buf bytes.Buffer
+	dnsStats = newDNSMetrics()
+	dnsQueryLog = newDNSQueryLogger(&buf)
+	t.Cleanup(func() {
+		dnsStats, dnsQueryLog = originalStats, originalLog
+	})
+	resetSubdomains()
+
+	h := withMetrics(handle)
+	send := func(name string, qtype uint16) {
+		r := new(dns.Msg)
+		r.SetQuestion(name, qtype)
+		h(&testResponseWriter{}, r)
+	}
+
+	send("pipe.t.isucon.pw.", dns.TypeA)
+	// クエリログは有効にした後のクエリだけ書き出す
+	dnsQueryLog.setEnabled(true)
+	send("pipe.t.isucon.pw.", dns.TypeAAAA)
+	// 存在しない名前は応答しない(NegativePolicy=drop)
+	send("nosuchuser.t.isucon.pw.", dns.TypeA)
+
+	res := dnsStats.snapshot(1)
+	if res.Queries != 3 || res.Answered != 2 || res.Dropped != 1 {
+		t.Errorf("queries/answered/dropped = %d/%d/%d, want 3/2/1", res.Queries, res.Answered, res.Dropped)
+	}
+	if res.QTypes["A"] != 2 || res.QTypes["AAAA"] != 1 {
+		t.Errorf("qtypes = %v", res.QTypes)
+	}
+	if res.Rcodes["NOERROR"] != 2 {
+		t.Errorf("rcodes = %v", res.Rcodes)
+	}
+	if len(res.TopNames) != 1 || res.TopNames[0] != (DNSNameCount{Name: "pipe.t.isucon.pw.", Count: 2}) {
+		t.Errorf("top_names = %v", res.TopNames)
+	}
+	udp := res.Latency["udp"]
+	if udp.Count != 3 || udp.Buckets[len(udp.Buckets)-1].Count != 3 {
+		t.Errorf("latency = %+v", udp)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("query log lines = %d, want 2: %s", len(lines), buf.String())
+	}
+	var entry DNSQueryLogEntry
+	if err := json.Unmarshal(lines[1], &entry); err != nil {
+		t.Fatalf("failed to decode query log: %+v", err)
+	}
+	if entry.Name != "nosuchuser.t.isucon.pw." || entry.Type != "A" || !entry.Dropped {
+		t.Errorf("query log = %+v", entry)
+	}
+}
+
+// ランダムな名前が大量に来ても、保持する名前の数は増えず、よく引かれる名前は残ること
+func TestDNSMetricsTopNames(t *testing.T) {
+	s := newDNSMetrics()
+	q := func(name string) dns.Question {
+		return dns.Question{Name: name, Qtype: dns.TypeA, Qclass: dns.ClassINET}
+	}
+	for i := 0; i < 100; i++ {
+		s.observe("udp", q("pipe.t.isucon.pw."), nil, time.Millisecond)
+	}
+	for i := 0; i < dnsTopNamesCapacity*3; i++ {
+		s.observe("udp", q(fmt.Sprintf("random%d.t.isucon.pw.", i)), nil, time.Millisecond)
+	}
+
+	names := 0
+	for i := range s.names {
+		names += len(s.names[i].names)
+	}
+	if names > dnsTopNamesCapacity {
+		t.Errorf("len(names) = %d, want <= %d", names, dnsTopNamesCapacity)
+	}
+	res := s.snapshot(1)
+	if len(res.TopNames) != 1 || res.TopNames[0].Name != "pipe.t.isucon.pw." {
+		t.Errorf("top_names = %v", res.TopNames)
+	}
+}
+
+// dnstap形式では、クエリと応答をそれぞれ1フレームとして書き出すこと
+func TestDNSQueryLogDNSTap(t *testing.T) {
+	originalLog := dnsQueryLog
+	t.Cleanup(func() { dnsQueryLog = originalLog })
+	resetSubdomains()
+
+	var buf bytes.Buffer
+	w, err := dnstap.NewWriter(&buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dnsQueryLog = newDNSTapQueryLogger(w)
+	dnsQueryLog.setEnabled(true)
+
+	h := withMetrics(handle)
+	for _, name := range []string{"pipe.t.isucon.pw.", "nosuchuser.t.isucon.pw."} {
+		r := new(dns.Msg)
+		r.SetQuestion(name, dns.TypeA)
+		h(&testResponseWriter{}, r)
+	}
+
+	reader, err := dnstap.NewReader(&buf, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := dnstap.NewDecoder(reader, 64*1024)
+	var types []dnstap.Message_Type
+	for {
+		var tap dnstap.Dnstap
+		if err := dec.Decode(&tap); err != nil {
+			break
+		}
+		msg := tap.GetMessage()
+		types = append(types, msg.GetType())
+		if !net.IP(msg.GetQueryAddress()).Equal(net.ParseIP("192.0.2.1")) || msg.GetSocketProtocol() != dnstap.SocketProtocol_UDP {
+			t.Errorf("message = %v", msg)
+		}
+		var q dns.Msg
+		if err := q.Unpack(msg.GetQueryMessage()); err != nil {
+			t.Errorf("failed to unpack query: %+v", err)
+		}
+		if msg.GetType() == dnstap.Message_AUTH_RESPONSE {
+			var res dns.Msg
+			if err := res.Unpack(msg.GetResponseMessage()); err != nil || len(res.Answer) != 1 {
+				t.Errorf("response = %v, %+v", res, err)
+			}
+		}
+	}
+	// 応答しなかったクエリはAUTH_QUERYだけ
+	want := []dnstap.Message_Type{dnstap.Message_AUTH_QUERY, dnstap.Message_AUTH_RESPONSE, dnstap.Message_AUTH_QUERY}
+	if !slices.Equal(types, want) {
+		t.Errorf("types = %v, want %v", types, want)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// サブドメインのAレコードで返すAPPサーバ
+type dnsAppServer struct {
+	Address string
+	// 重み(大きいほど先頭で返す回数が増える)
+	Weight int
+}
+
+// APPサーバのプール
+// クエリごとに重み付きラウンドロビンで先頭を変えて、ヘルスチェックに通っているサーバだけを返す
+type appServerPool struct {
+	servers []dnsAppServer
+	ips     []net.IP
+	// 先頭に返すサーバの順番(serversの添字)
+	schedule []int
+	next     atomic.Uint64
+	healthy  []atomic.Bool
+}
+
+type DNSAppServerStatus struct {
+	Address string `json:"address"`
+	Weight  int    `json:"weight"`
+	Healthy bool   `json:"healthy"`
+}
+
+var dnsAppPool = newAppServerPool(dnsConf.AppServers)
+
+func newAppServerPool(servers []dnsAppServer) *appServerPool {
+	p := &appServerPool{
+		servers: servers,
+		ips:     make([]net.IP, len(servers)),
+		healthy: make([]atomic.Bool, len(servers)),
+	}
+	for i, s := range servers {
+		p.ips[i] = net.ParseIP(s.Address).To4()
+		// 最初のヘルスチェックが終わるまでは全て返す
+		p.healthy[i].Store(true)
+	}
+
+	// smooth weighted round-robin (nginxと同じ方式) で、重みの合計回分の順番を作っておく
+	total := 0
+	for _, s := range servers {
+		total += s.Weight
+	}
+	current := make([]int, len(servers))
+	for step := 0; step < total; step++ {
+		best := 0
+		for i, s := range servers {
+			current[i] += s.Weight
+			if current[i] > current[best] {
+				best = i
+			}
+		}
+		current[best] -= total
+		p.schedule = append(p.schedule, best)
+	}
+	return p
+}
+
+// nameに対するAレコード
+// 全てのサーバがヘルスチェックに落ちている場合は、名前解決できなくなるよりはましなので全て返す
+func (p *appServerPool) records(name string) []dns.RR {
+	if len(p.schedule) == 0 {
+		return nil
+	}
+	n := p.next.Add(1) - 1
+	first := -1
+	for i := 0; i < len(p.schedule); i++ {
+		idx := p.schedule[(n+uint64(i))%uint64(len(p.schedule))]
+		if p.healthy[idx].Load() {
+			first = idx
+			break
+		}
+	}
+	failOpen := first < 0
+	if failOpen {
+		first = p.schedule[n%uint64(len(p.schedule))]
+	}
+
+	rrs := make([]dns.RR, 0, len(p.servers))
+	for i := 0; i < len(p.servers); i++ {
+		idx := (first + i) % len(p.servers)
+		if !failOpen && !p.healthy[idx].Load() {
+			continue
+		}
+		rrs = append(rrs, &dns.A{
+			Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeA, Class: dns.ClassINET, Ttl: dnsConf.AnswerTTL},
+			A:   p.ips[idx],
+		})
+	}
+	return rrs
+}
+
+func (p *appServerPool) contains(address string) bool {
+	for _, s := range p.servers {
+		if s.Address == address {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *appServerPool) status() []DNSAppServerStatus {
+	statuses := make([]DNSAppServerStatus, len(p.servers))
+	for i, s := range p.servers {
+		statuses[i] = DNSAppServerStatus{Address: s.Address, Weight: s.Weight, Healthy: p.healthy[i].Load()}
+	}
+	return statuses
+}
+
+// 全てのサーバの /api/tag を叩いて、200を返したものを正常とする
+func (p *appServerPool) checkHealth(client *http.Client, port int) {
+	var wg sync.WaitGroup
+	for i, s := range p.servers {
+		wg.Add(1)
+		go func(i int, s dnsAppServer) {
+			defer wg.Done()
+			url := fmt.Sprintf("http://%s/api/tag", net.JoinHostPort(s.Address, strconv.Itoa(port)))
+			healthy := false
+			res, err := client.Get(url)
+			if err == nil {
+				res.Body.Close()
+				healthy = res.StatusCode == http.StatusOK
+			}
+			if p.healthy[i].Swap(healthy) != healthy {
+				log.Printf("app server %s is now healthy=%v (err=%v)", s.Address, healthy, err)
+			}
+		}(i, s)
+	}
+	wg.Wait()
+}
+
+func (p *appServerPool) runHealthCheck(interval time.Duration, port int) {
+	client := &http.Client{Timeout: min(interval, 2*time.Second)}
+	for {
+		p.checkHealth(client, port)
+		time.Sleep(interval)
+	}
+}
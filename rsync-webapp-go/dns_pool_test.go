@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// 先頭に返したアドレスごとの回数
+func countFirstAddresses(p *appServerPool, n int) map[string]int {
+	counts := map[string]int{}
+	for i := 0; i < n; i++ {
+		rrs := p.records("pipe.t.isucon.pw.")
+		if len(rrs) == 0 {
+			continue
+		}
+		counts[rrs[0].(*dns.A).A.String()]++
+	}
+	return counts
+}
+
+func TestAppServerPool(t *testing.T) {
+	servers := []dnsAppServer{
+		{Address: "192.168.0.12", Weight: 2},
+		{Address: "192.168.0.11", Weight: 1},
+	}
+
+	t.Run("重みに応じて先頭のアドレスを変える", func(t *testing.T) {
+		p := newAppServerPool(servers)
+		counts := countFirstAddresses(p, 300)
+		if counts["192.168.0.12"] != 200 || counts["192.168.0.11"] != 100 {
+			t.Errorf("counts = %v, want 200:100", counts)
+		}
+		if rrs := p.records("pipe.t.isucon.pw."); len(rrs) != 2 {
+			t.Errorf("len(records) = %d, want 2", len(rrs))
+		}
+	})
+
+	t.Run("ヘルスチェックに落ちたサーバは返さない", func(t *testing.T) {
+		p := newAppServerPool(servers)
+		p.healthy[0].Store(false)
+		counts := countFirstAddresses(p, 30)
+		if counts["192.168.0.12"] != 0 || counts["192.168.0.11"] != 30 {
+			t.Errorf("counts = %v, want only 192.168.0.11", counts)
+		}
+		if rrs := p.records("pipe.t.isucon.pw."); len(rrs) != 1 {
+			t.Errorf("len(records) = %d, want 1", len(rrs))
+		}
+	})
+
+	t.Run("全て落ちている場合は全て返す", func(t *testing.T) {
+		p := newAppServerPool(servers)
+		p.healthy[0].Store(false)
+		p.healthy[1].Store(false)
+		if rrs := p.records("pipe.t.isucon.pw."); len(rrs) != 2 {
+			t.Errorf("len(records) = %d, want 2", len(rrs))
+		}
+	})
+}
+
+func TestAppServerPoolHealthCheck(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tag" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(ts.Close)
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, portStr, _ := net.SplitHostPort(u.Host)
+	port, _ := strconv.Atoi(portStr)
+
+	// 192.0.2.0/24 はどこにも繋がらない
+	p := newAppServerPool([]dnsAppServer{
+		{Address: host, Weight: 1},
+		{Address: "192.0.2.1", Weight: 1},
+	})
+	p.checkHealth(&http.Client{Timeout: 200 * time.Millisecond}, port)
+
+	statuses := p.status()
+	if !statuses[0].Healthy {
+		t.Errorf("%s should be healthy", statuses[0].Address)
+	}
+	if statuses[1].Healthy {
+		t.Errorf("%s should be unhealthy", statuses[1].Address)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dnstap/golang-dnstap"
+	"github.com/miekg/dns"
+)
+
+// クエリログの形式
+const (
+	// 1クエリ1行のJSON
+	dnsQueryLogJSON = "json"
+	// dnstap(Frame Streams)。AUTH_QUERYと、応答した場合はAUTH_RESPONSEを書き出す
+	dnsQueryLogDNSTap = "dnstap"
+)
+
+// DNSクエリログ
+// 量が多いので、普段は無効にしておいて管理用APIから必要な時だけ有効にする
+type dnsQueryLogger struct {
+	enabled atomic.Bool
+	format  string
+
+	mu  sync.Mutex
+	enc *json.Encoder
+	tap *dnstap.Encoder
+	// dnstapの書き出し先(ファイルならクエリごとにFlushする)
+	tapWriter dnstap.Writer
+}
+
+type DNSQueryLogEntry struct {
+	Time          time.Time `json:"time"`
+	Client        string    `json:"client"`
+	Proto         string    `json:"proto"`
+	Name          string    `json:"name"`
+	Type          string    `json:"type"`
+	Rcode         string    `json:"rcode,omitempty"`
+	Answers       int       `json:"answers"`
+	Dropped       bool      `json:"dropped"`
+	LatencyMicros int64     `json:"latency_us"`
+}
+
+var dnsQueryLog = newDNSQueryLogger(os.Stdout)
+
+func newDNSQueryLogger(out io.Writer) *dnsQueryLogger {
+	return &dnsQueryLogger{format: dnsQueryLogJSON, enc: json.NewEncoder(out)}
+}
+
+func newDNSTapQueryLogger(w dnstap.Writer) *dnsQueryLogger {
+	return &dnsQueryLogger{format: dnsQueryLogDNSTap, tap: dnstap.NewEncoder(w), tapWriter: w}
+}
+
+// 出力先を開いてクエリログを作る
+// pathが空か"-"なら標準出力、dnstapで"unix:"から始まる場合はそのUnixドメインソケット(dnstapのコレクタ)に送る
+func openDNSQueryLogger(path, format string) (*dnsQueryLogger, error) {
+	switch format {
+	case "", dnsQueryLogJSON:
+		out, err := openDNSQueryLog(path)
+		if err != nil {
+			return nil, err
+		}
+		return newDNSQueryLogger(out), nil
+	case dnsQueryLogDNSTap:
+		if socket, ok := strings.CutPrefix(path, "unix:"); ok {
+			w := dnstap.NewSocketWriter(&net.UnixAddr{Name: socket, Net: "unix"}, &dnstap.SocketWriterOptions{
+				Timeout:       time.Second,
+				FlushTimeout:  time.Second,
+				RetryInterval: 10 * time.Second,
+				Dialer:        &net.Dialer{Timeout: time.Second},
+			})
+			return newDNSTapQueryLogger(w), nil
+		}
+		out, err := openDNSQueryLog(path)
+		if err != nil {
+			return nil, err
+		}
+		w, err := dnstap.NewWriter(out, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start dnstap stream: %w", err)
+		}
+		return newDNSTapQueryLogger(w), nil
+	default:
+		return nil, fmt.Errorf("unknown dns query log format %q", format)
+	}
+}
+
+// 出力先のファイルを開く("-"なら標準出力)
+func openDNSQueryLog(path string) (io.Writer, error) {
+	if path == "" || path == "-" {
+		return os.Stdout, nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dns query log %s: %w", path, err)
+	}
+	return f, nil
+}
+
+func (l *dnsQueryLogger) setEnabled(enabled bool) {
+	l.enabled.Store(enabled)
+}
+
+func (l *dnsQueryLogger) isEnabled() bool {
+	return l.enabled.Load()
+}
+
+// rはクエリ、mは応答(応答しなかった場合はnil)
+func (l *dnsQueryLogger) write(client net.Addr, proto string, r, m *dns.Msg, start time.Time, elapsed time.Duration) {
+	if !l.isEnabled() {
+		return
+	}
+	if l.tap != nil {
+		l.writeDNSTap(client, proto, r, m, start, elapsed)
+		return
+	}
+
+	q := r.Question[0]
+	entry := DNSQueryLogEntry{
+		Time:          start.Add(elapsed),
+		Proto:         proto,
+		Name:          q.Name,
+		Type:          dns.Type(q.Qtype).String(),
+		Dropped:       m == nil,
+		LatencyMicros: elapsed.Microseconds(),
+	}
+	if client != nil {
+		entry.Client = client.String()
+	}
+	if m != nil {
+		entry.Rcode = dns.RcodeToString[m.Rcode]
+		entry.Answers = len(m.Answer)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// 書き込みに失敗してもDNSの応答は止めない
+	l.enc.Encode(&entry)
+}
+
+func (l *dnsQueryLogger) writeDNSTap(client net.Addr, proto string, r, m *dns.Msg, start time.Time, elapsed time.Duration) {
+	query, err := r.Pack()
+	if err != nil {
+		return
+	}
+	protocol := dnstap.SocketProtocol_UDP
+	if proto == "tcp" {
+		protocol = dnstap.SocketProtocol_TCP
+	}
+	var addr netip.AddrPort
+	switch a := client.(type) {
+	case *net.UDPAddr:
+		addr = a.AddrPort()
+	case *net.TCPAddr:
+		addr = a.AddrPort()
+	}
+	ip := addr.Addr().Unmap()
+	querySec, queryNsec := uint64(start.Unix()), uint32(start.Nanosecond())
+	newMessage := func(typ dnstap.Message_Type) *dnstap.Message {
+		msg := &dnstap.Message{
+			Type:           typ.Enum(),
+			SocketProtocol: protocol.Enum(),
+			QueryMessage:   query,
+			QueryTimeSec:   &querySec,
+			QueryTimeNsec:  &queryNsec,
+		}
+		if ip.IsValid() {
+			family := dnstap.SocketFamily_INET6
+			if ip.Is4() {
+				family = dnstap.SocketFamily_INET
+			}
+			port := uint32(addr.Port())
+			msg.SocketFamily = family.Enum()
+			msg.QueryAddress = ip.AsSlice()
+			msg.QueryPort = &port
+		}
+		return msg
+	}
+
+	frames := []*dnstap.Message{newMessage(dnstap.Message_AUTH_QUERY)}
+	if m != nil {
+		response, err := m.Pack()
+		if err != nil {
+			return
+		}
+		end := start.Add(elapsed)
+		responseSec, responseNsec := uint64(end.Unix()), uint32(end.Nanosecond())
+		msg := newMessage(dnstap.Message_AUTH_RESPONSE)
+		msg.ResponseMessage = response
+		msg.ResponseTimeSec, msg.ResponseTimeNsec = &responseSec, &responseNsec
+		frames = append(frames, msg)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// 書き込みに失敗してもDNSの応答は止めない
+	for _, frame := range frames {
+		l.tap.Encode(&dnstap.Dnstap{
+			Type:    dnstap.Dnstap_MESSAGE.Enum(),
+			Version: []byte("isupipe"),
+			Message: frame,
+		})
+	}
+	if f, ok := l.tapWriter.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+}
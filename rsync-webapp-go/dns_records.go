@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/miekg/dns"
+)
+
+const dnsRecordsFileEnvKey = "ISUCON13_DNS_RECORDS_FILE"
+
+// 起動時に読み込むレコードファイルのパス
+// 空の場合はdefaultSubdomainsを使う
+var dnsRecordsFile string
+
+// レコードファイルを読み込んで、サブドメイン(FQDN)の一覧とレコードを返す
+// 拡張子が.csvならCSV(1列目がドメイン名)、それ以外はゾーンファイルとして扱う
+// CSVの名前はAPPサーバを指し、ゾーンファイルのレコードはそのまま返す
+func loadRecordsFile(path string) ([]string, []dns.RR, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		names, err := parseRecordsCSV(f)
+		return names, nil, err
+	}
+	records, err := parseRecordsZone(f, path)
+	return nil, records, err
+}
+
+// isudns_records.csv の形式
+// t.isucon.pw
+// ns1.t.isucon.pw
+// ...
+func parseRecordsCSV(r io.Reader) ([]string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.Comment = '#'
+
+	names := []string{}
+	seen := map[string]struct{}{}
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSpace(record[0])
+		if name == "" {
+			continue
+		}
+		name = dns.CanonicalName(name)
+		if !dns.IsSubDomain(dnsConf.Zone, name) {
+			return nil, fmt.Errorf("%s is out of zone %s", name, dnsConf.Zone)
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// 一般的なゾーンファイル形式
+// $ORIGINが無い場合はゾーンの頂点を起点とする
+// ゾーン頂点のSOA/NSは設定から作るので読み飛ばす
+func parseRecordsZone(r io.Reader, path string) ([]dns.RR, error) {
+	zp := dns.NewZoneParser(r, dnsConf.Zone, path)
+
+	records := []dns.RR{}
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		name := dns.CanonicalName(rr.Header().Name)
+		if !dns.IsSubDomain(dnsConf.Zone, name) {
+			return nil, fmt.Errorf("%s is out of zone %s", name, dnsConf.Zone)
+		}
+		rrtype := rr.Header().Rrtype
+		if name == dnsConf.Zone && (rrtype == dns.TypeSOA || rrtype == dns.TypeNS) {
+			continue
+		}
+		rr.Header().Name = name
+		records = append(records, rr)
+	}
+	if err := zp.Err(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// SIGHUPを受けたらレコードファイルを読み直す
+func watchReloadSignal() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	for range ch {
+		if err := reloadSubdomains(); err != nil {
+			log.Printf("failed to reload DNS records file %s: %+v", dnsRecordsFile, err)
+			continue
+		}
+		log.Printf("reloaded DNS records file %s", dnsRecordsFile)
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"net"
+	"net/netip"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Response Rate Limiting (RRL)
+// 送信元のプレフィックスごとに応答数を制限して、水責めやリフレクション攻撃からAPPサーバを守る
+// 制限にかかった応答は捨てるが、slip回に1回はTC=1の空応答を返してTCPで再送してもらう
+// (正規のリゾルバはTCPで再送してくるので、名前解決できなくなることはない)
+type rrlConfig struct {
+	// 1秒あたりに許可する通常応答の数(0なら制限しない)
+	ResponsesPerSecond int
+	// 1秒あたりに許可するNXDOMAIN/NODATA/REFUSEDの数(0ならResponsesPerSecondと同じ)
+	ErrorsPerSecond int
+	// 制限にかかった応答のうち、何回に1回TC=1で返すか(0なら全て捨てる、1なら全てTC=1)
+	Slip int
+	// 送信元をまとめるプレフィックス長
+	IPv4PrefixLen int
+	IPv6PrefixLen int
+	// 制限しない送信元
+	Whitelist []netip.Prefix
+}
+
+type rrlKey struct {
+	prefix netip.Prefix
+	isErr  bool
+}
+
+type rrlBucket struct {
+	tokens  float64
+	last    time.Time
+	limited int
+}
+
+type responseRateLimiter struct {
+	conf rrlConfig
+	now  func() time.Time
+
+	mu      sync.Mutex
+	buckets map[rrlKey]*rrlBucket
+
+	allowed atomic.Int64
+	dropped atomic.Int64
+	slipped atomic.Int64
+	exempt  atomic.Int64
+}
+
+type RRLStats struct {
+	Enabled bool  `json:"enabled"`
+	Allowed int64 `json:"allowed"`
+	Limited int64 `json:"limited"`
+	Dropped int64 `json:"dropped"`
+	Slipped int64 `json:"slipped"`
+	Exempt  int64 `json:"exempt"`
+}
+
+// nilの場合はRRLが無効
+var dnsRRL *responseRateLimiter
+
+func newResponseRateLimiter(conf rrlConfig) *responseRateLimiter {
+	if conf.ErrorsPerSecond == 0 {
+		conf.ErrorsPerSecond = conf.ResponsesPerSecond
+	}
+	return &responseRateLimiter{
+		conf:    conf,
+		now:     time.Now,
+		buckets: map[rrlKey]*rrlBucket{},
+	}
+}
+
+type rrlAction int
+
+const (
+	rrlSend rrlAction = iota
+	rrlDrop
+	rrlSlip
+)
+
+// 送信元addrに応答mを返してよいか判定する
+func (l *responseRateLimiter) check(addr netip.Addr, m *dns.Msg) rrlAction {
+	addr = addr.Unmap()
+	for _, prefix := range l.conf.Whitelist {
+		if prefix.Contains(addr) {
+			l.exempt.Add(1)
+			return rrlSend
+		}
+	}
+
+	bits := l.conf.IPv6PrefixLen
+	if addr.Is4() {
+		bits = l.conf.IPv4PrefixLen
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		l.allowed.Add(1)
+		return rrlSend
+	}
+	key := rrlKey{prefix: prefix, isErr: m.Rcode != dns.RcodeSuccess || len(m.Answer) == 0}
+	rate := float64(l.conf.ResponsesPerSecond)
+	if key.isErr {
+		rate = float64(l.conf.ErrorsPerSecond)
+	}
+
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &rrlBucket{tokens: rate, last: now}
+		l.buckets[key] = b
+	}
+	// トークンバケット: 1秒でrate個回復し、rate個まで貯まる
+	b.tokens += now.Sub(b.last).Seconds() * rate
+	if b.tokens > rate {
+		b.tokens = rate
+	}
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		l.allowed.Add(1)
+		return rrlSend
+	}
+
+	b.limited++
+	if l.conf.Slip > 0 && b.limited%l.conf.Slip == 0 {
+		l.slipped.Add(1)
+		return rrlSlip
+	}
+	l.dropped.Add(1)
+	return rrlDrop
+}
+
+// しばらく使われていないバケットを捨てる
+func (l *responseRateLimiter) sweep(idle time.Duration) {
+	now := l.now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > idle {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (l *responseRateLimiter) stats() RRLStats {
+	if l == nil {
+		return RRLStats{}
+	}
+	return RRLStats{
+		Enabled: true,
+		Allowed: l.allowed.Load(),
+		Limited: l.dropped.Load() + l.slipped.Load(),
+		Dropped: l.dropped.Load(),
+		Slipped: l.slipped.Load(),
+		Exempt:  l.exempt.Load(),
+	}
+}
+
+// RRLを挟むdns.ResponseWriter
+// 送信元を偽装できるUDPのみ制限する
+type rrlResponseWriter struct {
+	dns.ResponseWriter
+	limiter *responseRateLimiter
+}
+
+func (w *rrlResponseWriter) WriteMsg(m *dns.Msg) error {
+	udpAddr, ok := w.RemoteAddr().(*net.UDPAddr)
+	if !ok {
+		return w.ResponseWriter.WriteMsg(m)
+	}
+	addr, ok := netip.AddrFromSlice(udpAddr.IP)
+	if !ok {
+		return w.ResponseWriter.WriteMsg(m)
+	}
+
+	switch w.limiter.check(addr, m) {
+	case rrlDrop:
+		return nil
+	case rrlSlip:
+		tc := new(dns.Msg)
+		tc.MsgHdr = m.MsgHdr
+		tc.Question = m.Question
+		tc.Truncated = true
+		return w.ResponseWriter.WriteMsg(tc)
+	}
+	return w.ResponseWriter.WriteMsg(m)
+}
+
+func withRRL(next dns.HandlerFunc) dns.HandlerFunc {
+	if dnsRRL == nil {
+		return next
+	}
+	go func() {
+		for range time.Tick(10 * time.Second) {
+			dnsRRL.sweep(time.Minute)
+		}
+	}()
+	return func(w dns.ResponseWriter, r *dns.Msg) {
+		next(&rrlResponseWriter{ResponseWriter: w, limiter: dnsRRL}, r)
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+func TestResponseRateLimiter(t *testing.T) {
+	answer := new(dns.Msg)
+	answer.Answer = []dns.RR{mustNewRR("pipe.t.isucon.pw. 120 IN A 192.168.0.12")}
+
+	testCases := []struct {
+		name       string
+		conf       rrlConfig
+		addrs      []string
+		wantSend   int
+		wantSlip   int
+		wantDrop   int
+		wantExempt int
+	}{
+		{
+			name:     "制限内なら全て応答する",
+			conf:     rrlConfig{ResponsesPerSecond: 10, Slip: 2, IPv4PrefixLen: 24, IPv6PrefixLen: 56},
+			addrs:    repeat("192.0.2.1", 10),
+			wantSend: 10,
+		},
+		{
+			name:     "制限を超えたらslip回に1回TC=1で返し、残りは捨てる",
+			conf:     rrlConfig{ResponsesPerSecond: 5, Slip: 2, IPv4PrefixLen: 24, IPv6PrefixLen: 56},
+			addrs:    repeat("192.0.2.1", 11),
+			wantSend: 5,
+			wantSlip: 3,
+			wantDrop: 3,
+		},
+		{
+			name:     "同じプレフィックスの送信元はまとめて数える",
+			conf:     rrlConfig{ResponsesPerSecond: 2, Slip: 0, IPv4PrefixLen: 24, IPv6PrefixLen: 56},
+			addrs:    []string{"192.0.2.1", "192.0.2.2", "192.0.2.3", "198.51.100.1"},
+			wantSend: 3,
+			wantDrop: 1,
+		},
+		{
+			name:       "ホワイトリストの送信元は制限しない",
+			conf:       rrlConfig{ResponsesPerSecond: 1, Slip: 2, IPv4PrefixLen: 24, IPv6PrefixLen: 56, Whitelist: []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}},
+			addrs:      repeat("192.0.2.1", 5),
+			wantSend:   5,
+			wantExempt: 5,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newResponseRateLimiter(tt.conf)
+			now := time.Date(2023, 11, 25, 10, 0, 0, 0, time.UTC)
+			l.now = func() time.Time { return now }
+
+			var send, slip, drop int
+			for _, addr := range tt.addrs {
+				switch l.check(netip.MustParseAddr(addr), answer) {
+				case rrlSend:
+					send++
+				case rrlSlip:
+					slip++
+				case rrlDrop:
+					drop++
+				}
+			}
+			if send != tt.wantSend || slip != tt.wantSlip || drop != tt.wantDrop {
+				t.Errorf("got send/slip/drop: %d/%d/%d, want: %d/%d/%d", send, slip, drop, tt.wantSend, tt.wantSlip, tt.wantDrop)
+			}
+			stats := l.stats()
+			if stats.Limited != int64(slip+drop) || stats.Exempt != int64(tt.wantExempt) {
+				t.Errorf("got stats: %+v", stats)
+			}
+		})
+	}
+}
+
+func TestResponseRateLimiterRefill(t *testing.T) {
+	l := newResponseRateLimiter(rrlConfig{ResponsesPerSecond: 1, Slip: 0, IPv4PrefixLen: 24, IPv6PrefixLen: 56})
+	now := time.Date(2023, 11, 25, 10, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	nxdomain := new(dns.Msg)
+	nxdomain.Rcode = dns.RcodeNameError
+	addr := netip.MustParseAddr("2001:db8::1")
+
+	if got := l.check(addr, nxdomain); got != rrlSend {
+		t.Fatalf("1回目は応答するはず: %v", got)
+	}
+	if got := l.check(addr, nxdomain); got != rrlDrop {
+		t.Fatalf("2回目は捨てるはず: %v", got)
+	}
+	now = now.Add(time.Second)
+	if got := l.check(addr, nxdomain); got != rrlSend {
+		t.Fatalf("1秒後は応答するはず: %v", got)
+	}
+}
+
+func repeat(s string, n int) []string {
+	ss := make([]string, n)
+	for i := range ss {
+		ss[i] = s
+	}
+	return ss
+}
@@ -0,0 +1,523 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+// handleのテスト用のdns.ResponseWriter
+// 書き込まれたメッセージを保持する
+type testResponseWriter struct {
+	dns.ResponseWriter
+	msg *dns.Msg
+}
+
+func (w *testResponseWriter) WriteMsg(m *dns.Msg) error {
+	w.msg = m
+	return nil
+}
+
+func (w *testResponseWriter) RemoteAddr() net.Addr {
+	return &net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 53}
+}
+
+func (w *testResponseWriter) LocalAddr() net.Addr {
+	return &net.UDPAddr{IP: net.ParseIP("192.0.2.53"), Port: 53}
+}
+
+func mustNewRR(s string) dns.RR {
+	rr, err := newRR(s)
+	if err != nil {
+		panic(err)
+	}
+	return rr
+}
+
+// handleにクエリを投げて、応答を返す(応答しなかった場合はnil)
+func query(name string, qtype uint16) *dns.Msg {
+	r := new(dns.Msg)
+	r.SetQuestion(name, qtype)
+	w := &testResponseWriter{}
+	handle(w, r)
+	return w.msg
+}
+
+func TestAddSubdomain(t *testing.T) {
+	t.Cleanup(resetSubdomains)
+	resetSubdomains()
+
+	before := subdomains.Load()
+	addSubdomain("newuser0.t.isucon.pw.")
+	// 同じ名前や初期状態の名前を登録しても一覧は増えないこと
+	addSubdomain("newuser0.t.isucon.pw.")
+	addSubdomain("NEWUSER0.t.isucon.pw.")
+	addSubdomain("pipe.t.isucon.pw.")
+	if len(addedSubdomains) != 1 {
+		t.Errorf("addedSubdomains = %v, want 1 name", addedSubdomains)
+	}
+
+	if !hasSubdomain("newuser0.t.isucon.pw.") {
+		t.Errorf("追加したサブドメインが見つからない")
+	}
+	// 読み込み中のスナップショットは書き換わらないこと
+	if _, ok := (*before)["newuser0.t.isucon.pw."]; ok {
+		t.Errorf("古いスナップショットが書き換えられている")
+	}
+
+	resetSubdomains()
+	if hasSubdomain("newuser0.t.isucon.pw.") {
+		t.Errorf("resetSubdomains後も追加したサブドメインが残っている")
+	}
+	if !hasSubdomain("pipe.t.isucon.pw.") {
+		t.Errorf("resetSubdomains後に初期状態のサブドメインが見つからない")
+	}
+}
+
+// 既定のサブドメインは設定のゾーンに合わせて作ること
+func TestDefaultSubdomainsFollowZone(t *testing.T) {
+	t.Cleanup(func() {
+		dnsConf = defaultDNSConfig()
+		reloadSubdomains()
+	})
+	dnsConf = defaultDNSConfig()
+	dnsConf.Zone = "u.example.com."
+	if err := reloadSubdomains(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"u.example.com.", "ns1.u.example.com.", "pipe.u.example.com.", "test001.u.example.com."} {
+		if !hasSubdomain(name) {
+			t.Errorf("%s が見つからない", name)
+		}
+	}
+	if hasSubdomain("pipe.t.isucon.pw.") {
+		t.Errorf("ゾーン外の既定のサブドメインが残っている")
+	}
+	if got := query("pipe.u.example.com.", dns.TypeA); got == nil || len(got.Answer) != 1 {
+		t.Errorf("got: %v", got)
+	}
+}
+
+// 登録ユーザ数が増えてもlookupの時間が変わらないこと
+// go test -run '^$' -bench BenchmarkHasSubdomain
+func BenchmarkHasSubdomain(b *testing.B) {
+	for _, size := range []int{1000, 10000, 100000} {
+		names := make([]string, size)
+		for i := range names {
+			names[i] = fmt.Sprintf("user%d.t.isucon.pw.", i)
+		}
+		subdomains.Store(newZoneData([][]string{names}, nil))
+
+		b.Run(fmt.Sprintf("registered=%d/hit", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				hasSubdomain(names[i%size])
+			}
+		})
+		b.Run(fmt.Sprintf("registered=%d/miss", size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				hasSubdomain("water-torture.t.isucon.pw.")
+			}
+		})
+		b.Run(fmt.Sprintf("registered=%d/parallel", size), func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					hasSubdomain(names[i%size])
+					i++
+				}
+			})
+		})
+	}
+	resetSubdomains()
+}
+
+func TestHandleNegativePolicy(t *testing.T) {
+	t.Cleanup(func() { dnsConf = defaultDNSConfig() })
+
+	testCases := []struct {
+		name      string
+		policy    dnsNegativePolicy
+		qname     string
+		qtype     uint16
+		wantReply bool
+		wantRcode int
+		wantSOA   bool
+	}{
+		{
+			name:      "存在する名前のAはNOERROR",
+			policy:    dnsNegativeDrop,
+			qname:     "pipe.t.isucon.pw.",
+			qtype:     dns.TypeA,
+			wantReply: true,
+			wantRcode: dns.RcodeSuccess,
+		},
+		{
+			name:      "存在する名前のAAAAはNODATA",
+			policy:    dnsNegativeDrop,
+			qname:     "pipe.t.isucon.pw.",
+			qtype:     dns.TypeAAAA,
+			wantReply: true,
+			wantRcode: dns.RcodeSuccess,
+			wantSOA:   true,
+		},
+		{
+			name:      "dropの場合、存在しない名前には応答しない",
+			policy:    dnsNegativeDrop,
+			qname:     "water-torture.t.isucon.pw.",
+			qtype:     dns.TypeA,
+			wantReply: false,
+		},
+		{
+			name:      "nxdomainの場合、存在しない名前にはNXDOMAINとSOA",
+			policy:    dnsNegativeNXDomain,
+			qname:     "water-torture.t.isucon.pw.",
+			qtype:     dns.TypeA,
+			wantReply: true,
+			wantRcode: dns.RcodeNameError,
+			wantSOA:   true,
+		},
+		{
+			name:      "refusedの場合、存在しない名前にはREFUSED",
+			policy:    dnsNegativeRefused,
+			qname:     "water-torture.t.isucon.pw.",
+			qtype:     dns.TypeA,
+			wantReply: true,
+			wantRcode: dns.RcodeRefused,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			dnsConf = defaultDNSConfig()
+			dnsConf.NegativePolicy = tt.policy
+
+			got := query(tt.qname, tt.qtype)
+			if (got != nil) != tt.wantReply {
+				t.Fatalf("got reply: %v, want reply: %v", got != nil, tt.wantReply)
+			}
+			if got == nil {
+				return
+			}
+			if got.Rcode != tt.wantRcode {
+				t.Errorf("got rcode: %s, want rcode: %s", dns.RcodeToString[got.Rcode], dns.RcodeToString[tt.wantRcode])
+			}
+			hasSOA := len(got.Ns) == 1 && got.Ns[0].Header().Rrtype == dns.TypeSOA
+			if hasSOA != tt.wantSOA {
+				t.Errorf("got soa in authority: %v, want: %v", hasSOA, tt.wantSOA)
+			}
+		})
+	}
+}
+
+func TestHandleRecordTypes(t *testing.T) {
+	t.Cleanup(func() {
+		dnsConf = defaultDNSConfig()
+		resetSubdomains()
+	})
+	dnsConf = defaultDNSConfig()
+	dnsConf.NegativePolicy = dnsNegativeNXDomain
+
+	zone := `
+$TTL 60
+pipe      IN A     192.168.0.12
+pipe      IN AAAA  2001:db8::12
+pipe      IN TXT   "isupipe"
+www       IN CNAME pipe
+alias     IN CNAME www
+dangling  IN CNAME nothing
+external  IN CNAME isucon.net.
+a.b       IN A     192.168.0.13
+`
+	records, err := parseRecordsZone(strings.NewReader(zone), "test.zone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	subdomains.Store(newZoneData([][]string{{"user0.t.isucon.pw."}}, records))
+
+	testCases := []struct {
+		name       string
+		qname      string
+		qtype      uint16
+		wantRcode  int
+		wantAnswer []string
+		wantSOA    bool
+	}{
+		{
+			name:       "AAAAはAAAAだけ返す",
+			qname:      "pipe.t.isucon.pw.",
+			qtype:      dns.TypeAAAA,
+			wantAnswer: []string{"pipe.t.isucon.pw.\t60\tIN\tAAAA\t2001:db8::12"},
+		},
+		{
+			name:       "TXTを返す",
+			qname:      "pipe.t.isucon.pw.",
+			qtype:      dns.TypeTXT,
+			wantAnswer: []string{"pipe.t.isucon.pw.\t60\tIN\tTXT\t\"isupipe\""},
+		},
+		{
+			name:    "ユーザのサブドメインにAAAAは無いのでNODATA",
+			qname:   "user0.t.isucon.pw.",
+			qtype:   dns.TypeAAAA,
+			wantSOA: true,
+		},
+		{
+			name:       "ユーザのサブドメインはAPPサーバのA",
+			qname:      "user0.t.isucon.pw.",
+			qtype:      dns.TypeA,
+			wantAnswer: []string{"user0.t.isucon.pw.\t120\tIN\tA\t192.168.0.12"},
+		},
+		{
+			name:  "CNAMEを辿る",
+			qname: "alias.t.isucon.pw.",
+			qtype: dns.TypeA,
+			wantAnswer: []string{
+				"alias.t.isucon.pw.\t60\tIN\tCNAME\twww.t.isucon.pw.",
+				"www.t.isucon.pw.\t60\tIN\tCNAME\tpipe.t.isucon.pw.",
+				"pipe.t.isucon.pw.\t60\tIN\tA\t192.168.0.12",
+			},
+		},
+		{
+			name:       "CNAMEクエリにはCNAMEだけ返す",
+			qname:      "www.t.isucon.pw.",
+			qtype:      dns.TypeCNAME,
+			wantAnswer: []string{"www.t.isucon.pw.\t60\tIN\tCNAME\tpipe.t.isucon.pw."},
+		},
+		{
+			name:       "CNAMEの先がゾーン内に無ければNXDOMAIN",
+			qname:      "dangling.t.isucon.pw.",
+			qtype:      dns.TypeA,
+			wantRcode:  dns.RcodeNameError,
+			wantAnswer: []string{"dangling.t.isucon.pw.\t60\tIN\tCNAME\tnothing.t.isucon.pw."},
+			wantSOA:    true,
+		},
+		{
+			name:       "ゾーン外のCNAMEは辿らない",
+			qname:      "external.t.isucon.pw.",
+			qtype:      dns.TypeA,
+			wantAnswer: []string{"external.t.isucon.pw.\t60\tIN\tCNAME\tisucon.net."},
+		},
+		{
+			name:    "途中のラベルはNODATA",
+			qname:   "b.t.isucon.pw.",
+			qtype:   dns.TypeA,
+			wantSOA: true,
+		},
+		{
+			name:       "大文字小文字を区別しない",
+			qname:      "PIPE.t.isucon.pw.",
+			qtype:      dns.TypeAAAA,
+			wantAnswer: []string{"pipe.t.isucon.pw.\t60\tIN\tAAAA\t2001:db8::12"},
+		},
+		{
+			name:       "ネームサーバはグルーと同じアドレス",
+			qname:      "ns1.t.isucon.pw.",
+			qtype:      dns.TypeA,
+			wantAnswer: []string{"ns1.t.isucon.pw.\t120\tIN\tA\t192.168.0.11"},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := query(tt.qname, tt.qtype)
+			if got == nil {
+				t.Fatal("応答が無い")
+			}
+			if got.Rcode != tt.wantRcode {
+				t.Errorf("got rcode: %s, want rcode: %s", dns.RcodeToString[got.Rcode], dns.RcodeToString[tt.wantRcode])
+			}
+			answer := make([]string, len(got.Answer))
+			for i, rr := range got.Answer {
+				answer[i] = rr.String()
+			}
+			if strings.Join(answer, "\n") != strings.Join(tt.wantAnswer, "\n") {
+				t.Errorf("got answer: %v, want answer: %v", answer, tt.wantAnswer)
+			}
+			hasSOA := len(got.Ns) == 1 && got.Ns[0].Header().Rrtype == dns.TypeSOA
+			if hasSOA != tt.wantSOA {
+				t.Errorf("got soa in authority: %v, want: %v", hasSOA, tt.wantSOA)
+			}
+		})
+	}
+}
+
+func TestAddAndDeleteRecords(t *testing.T) {
+	t.Cleanup(func() {
+		adminRecords = nil
+		resetSubdomains()
+	})
+	resetSubdomains()
+
+	if err := addRecord(mustNewRR("www9.t.isucon.pw. 60 IN CNAME pipe.t.isucon.pw.")); err != nil {
+		t.Fatal(err)
+	}
+	if got := query("www9.t.isucon.pw.", dns.TypeA); got == nil || len(got.Answer) != 2 {
+		t.Fatalf("CNAMEを辿ってAを返すはず: %v", got)
+	}
+	if err := addRecord(mustNewRR("www9.t.isucon.pw. 60 IN TXT \"x\"")); err == nil {
+		t.Errorf("CNAMEのある名前に他のレコードは追加できないはず")
+	}
+	if err := addRecord(mustNewRR("pipe.t.isucon.pw. 60 IN CNAME www.t.isucon.pw.")); err == nil {
+		t.Errorf("他のレコードのある名前にCNAMEは追加できないはず")
+	}
+	if err := addRecord(mustNewRR("isucon.net. 60 IN A 192.0.2.1")); err == nil {
+		t.Errorf("ゾーン外のレコードは追加できないはず")
+	}
+
+	if deleted := deleteRecords("www9.t.isucon.pw.", dns.TypeCNAME, ""); deleted != 1 {
+		t.Errorf("got deleted: %d, want: 1", deleted)
+	}
+	if hasSubdomain("www9.t.isucon.pw.") {
+		t.Errorf("削除したレコードが残っている")
+	}
+	if deleted := deleteRecords("pipe.t.isucon.pw.", 0, ""); deleted != 1 {
+		t.Errorf("got deleted: %d, want: 1", deleted)
+	}
+	if hasSubdomain("pipe.t.isucon.pw.") {
+		t.Errorf("削除したサブドメインが残っている")
+	}
+}
+
+func TestHandleMalformedQueries(t *testing.T) {
+	testCases := []struct {
+		name      string
+		msg       func() *dns.Msg
+		wantReply bool
+		wantRcode int
+	}{
+		{
+			name:      "質問が無い場合はFORMERR",
+			msg:       func() *dns.Msg { return &dns.Msg{} },
+			wantReply: true,
+			wantRcode: dns.RcodeFormatError,
+		},
+		{
+			name: "質問が複数ある場合はFORMERR",
+			msg: func() *dns.Msg {
+				r := new(dns.Msg).SetQuestion("pipe.t.isucon.pw.", dns.TypeA)
+				r.Question = append(r.Question, dns.Question{Name: "test001.t.isucon.pw.", Qtype: dns.TypeA, Qclass: dns.ClassINET})
+				return r
+			},
+			wantReply: true,
+			wantRcode: dns.RcodeFormatError,
+		},
+		{
+			name: "IN以外のクラスはREFUSED",
+			msg: func() *dns.Msg {
+				r := new(dns.Msg).SetQuestion("pipe.t.isucon.pw.", dns.TypeTXT)
+				r.Question[0].Qclass = dns.ClassCHAOS
+				return r
+			},
+			wantReply: true,
+			wantRcode: dns.RcodeRefused,
+		},
+		{
+			name: "QUERY以外のオペコードはNOTIMP",
+			msg: func() *dns.Msg {
+				r := new(dns.Msg).SetQuestion("pipe.t.isucon.pw.", dns.TypeA)
+				r.Opcode = dns.OpcodeUpdate
+				return r
+			},
+			wantReply: true,
+			wantRcode: dns.RcodeNotImplemented,
+		},
+		{
+			name: "EDNSのバージョンが0以外ならBADVERS",
+			msg: func() *dns.Msg {
+				r := new(dns.Msg).SetQuestion("pipe.t.isucon.pw.", dns.TypeA)
+				r.SetEdns0(1232, false)
+				r.IsEdns0().SetVersion(1)
+				return r
+			},
+			wantReply: true,
+			wantRcode: dns.RcodeBadVers,
+		},
+		{
+			name: "OPTが複数ある場合はFORMERR",
+			msg: func() *dns.Msg {
+				r := new(dns.Msg).SetQuestion("pipe.t.isucon.pw.", dns.TypeA)
+				r.SetEdns0(1232, false)
+				r.Extra = append(r.Extra, r.Extra[0])
+				return r
+			},
+			wantReply: true,
+			wantRcode: dns.RcodeFormatError,
+		},
+		{
+			name: "大きすぎるOPTはFORMERR",
+			msg: func() *dns.Msg {
+				r := new(dns.Msg).SetQuestion("pipe.t.isucon.pw.", dns.TypeA)
+				r.SetEdns0(1232, false)
+				opt := r.IsEdns0()
+				opt.Option = append(opt.Option, &dns.EDNS0_LOCAL{Code: dns.EDNS0LOCALSTART, Data: make([]byte, maxOPTLen)})
+				return r
+			},
+			wantReply: true,
+			wantRcode: dns.RcodeFormatError,
+		},
+		{
+			name: "応答には応答しない",
+			msg: func() *dns.Msg {
+				r := new(dns.Msg).SetQuestion("pipe.t.isucon.pw.", dns.TypeA)
+				r.Response = true
+				return r
+			},
+			wantReply: false,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &testResponseWriter{}
+			handle(w, tt.msg())
+			if (w.msg != nil) != tt.wantReply {
+				t.Fatalf("got reply: %v, want reply: %v", w.msg != nil, tt.wantReply)
+			}
+			if w.msg == nil {
+				return
+			}
+			if w.msg.Rcode != tt.wantRcode {
+				t.Errorf("got rcode: %s, want rcode: %s", dns.RcodeToString[w.msg.Rcode], dns.RcodeToString[tt.wantRcode])
+			}
+			// 拡張Rcodeを含めて、送信できる応答であること
+			if _, err := w.msg.Pack(); err != nil {
+				t.Errorf("failed to pack reply: %+v", err)
+			}
+		})
+	}
+}
+
+// どんなメッセージを受け取ってもpanicせず、送信できる応答を返すこと
+// go test -run '^$' -fuzz FuzzHandle
+func FuzzHandle(f *testing.F) {
+	seeds := []*dns.Msg{
+		new(dns.Msg).SetQuestion("pipe.t.isucon.pw.", dns.TypeA),
+		new(dns.Msg).SetQuestion("t.isucon.pw.", dns.TypeANY),
+		new(dns.Msg).SetQuestion("water-torture.t.isucon.pw.", dns.TypeAAAA),
+		new(dns.Msg).SetQuestion("t.isucon.pw.", dns.TypeAXFR),
+		new(dns.Msg).SetEdns0(1232, true),
+		{},
+	}
+	for _, m := range seeds {
+		buf, err := m.Pack()
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(buf)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r := new(dns.Msg)
+		if err := r.Unpack(data); err != nil {
+			return
+		}
+		w := &testResponseWriter{}
+		handle(w, r)
+		if w.msg == nil {
+			return
+		}
+		if _, err := w.msg.Pack(); err != nil {
+			t.Errorf("failed to pack reply to %v: %+v", r, err)
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// IXFR用に保持する差分の数
+	// これより古いシリアルからのIXFRはAXFRにフォールバックする
+	maxIXFRJournal = 1000
+	// ゾーン転送で1メッセージに詰めるレコード数
+	transferChunkSize = 500
+)
+
+// シリアル番号fromからtoへの差分
+type ixfrDelta struct {
+	from    uint32
+	to      uint32
+	deleted []dns.RR
+	added   []dns.RR
+}
+
+// IXFR用の差分の履歴(muSubdomainsで保護する)
+var ixfrJournal []ixfrDelta
+
+// ゾーンの変更をIXFR用に記録して、シリアル番号を進める
+// muSubdomainsを取ってから呼ぶこと
+func recordIXFRDelta(deleted, added []dns.RR) {
+	from := dnsSerial.Load()
+	to := from + 1
+	dnsSerial.Store(to)
+	ixfrJournal = append(ixfrJournal, ixfrDelta{from: from, to: to, deleted: deleted, added: added})
+	if len(ixfrJournal) > maxIXFRJournal {
+		ixfrJournal = ixfrJournal[len(ixfrJournal)-maxIXFRJournal:]
+	}
+}
+
+// ゾーンを作り直したので差分の履歴を捨てて、シリアル番号を進める
+// muSubdomainsを取ってから呼ぶこと
+func resetIXFRJournal() {
+	dnsSerial.Add(1)
+	ixfrJournal = nil
+}
+
+// serialから現在までの差分を返す(履歴が足りなければfalse)
+// muSubdomainsを取ってから呼ぶこと
+func ixfrDeltasSince(serial uint32) ([]ixfrDelta, bool) {
+	for i, delta := range ixfrJournal {
+		if delta.from == serial {
+			return ixfrJournal[i:], true
+		}
+	}
+	return nil, false
+}
+
+func remoteAddr(w dns.ResponseWriter) (netip.Addr, bool) {
+	switch addr := w.RemoteAddr().(type) {
+	case *net.UDPAddr:
+		a, ok := netip.AddrFromSlice(addr.IP)
+		return a.Unmap(), ok
+	case *net.TCPAddr:
+		a, ok := netip.AddrFromSlice(addr.IP)
+		return a.Unmap(), ok
+	}
+	return netip.Addr{}, false
+}
+
+func transferAllowed(w dns.ResponseWriter) bool {
+	addr, ok := remoteAddr(w)
+	if !ok {
+		return false
+	}
+	for _, prefix := range dnsConf.TransferAllow {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// AXFR/IXFRに応答する
+// セカンダリのPowerDNSなどにゾーンを転送できるように、ACLで許可した相手にのみ返す
+func serveTransfer(w dns.ResponseWriter, r *dns.Msg) {
+	q := r.Question[0]
+	m := new(dns.Msg)
+	m.SetReply(r)
+
+	if !transferAllowed(w) || !strings.EqualFold(q.Name, dnsConf.Zone) {
+		m.Rcode = dns.RcodeRefused
+		writeMsg(w, m)
+		return
+	}
+	m.Authoritative = true
+
+	var clientSerial uint32
+	if q.Qtype == dns.TypeIXFR {
+		if len(r.Ns) == 0 {
+			m.Rcode = dns.RcodeFormatError
+			writeMsg(w, m)
+			return
+		}
+		soa, ok := r.Ns[0].(*dns.SOA)
+		if !ok {
+			m.Rcode = dns.RcodeFormatError
+			writeMsg(w, m)
+			return
+		}
+		clientSerial = soa.Serial
+	}
+
+	_, isUDP := w.RemoteAddr().(*net.UDPAddr)
+
+	// 転送中にゾーンが変わらないように、シリアルとスナップショットを揃えて取る
+	muSubdomains.Lock()
+	soa := soaRR()
+	upToDate := q.Qtype == dns.TypeIXFR && clientSerial == soa.(*dns.SOA).Serial
+	var (
+		deltas      []ixfrDelta
+		incremental bool
+		rrs         []dns.RR
+	)
+	if q.Qtype == dns.TypeIXFR && !upToDate && !isUDP {
+		deltas, incremental = ixfrDeltasSince(clientSerial)
+	}
+	if !upToDate && !incremental && !isUDP {
+		rrs = zoneRecords()
+	}
+	muSubdomains.Unlock()
+
+	switch {
+	// 最新なのでSOAだけ返す
+	// UDPの場合は収まらないので、SOAだけ返してTCPで取り直してもらう(RFC 1995)
+	case upToDate, isUDP && q.Qtype == dns.TypeIXFR:
+		m.Answer = []dns.RR{soa}
+		writeMsg(w, m)
+		return
+	// AXFRはTCPのみ
+	case isUDP:
+		m.Rcode = dns.RcodeRefused
+		writeMsg(w, m)
+		return
+	}
+
+	// IXFR: SOA(新) [SOA(旧) 削除... SOA(新) 追加...]... SOA(新)
+	// AXFR: SOA 全レコード SOA
+	answer := []dns.RR{soa}
+	if incremental {
+		for _, delta := range deltas {
+			answer = append(answer, soaWithSerial(soa, delta.from))
+			answer = append(answer, delta.deleted...)
+			answer = append(answer, soaWithSerial(soa, delta.to))
+			answer = append(answer, delta.added...)
+		}
+	} else {
+		// zoneRecordsの先頭はSOA
+		answer = append(answer, rrs[1:]...)
+	}
+	answer = append(answer, soa)
+
+	for len(answer) > 0 {
+		n := min(len(answer), transferChunkSize)
+		chunk := new(dns.Msg)
+		chunk.SetReply(r)
+		chunk.Authoritative = true
+		chunk.Compress = true
+		chunk.Answer = answer[:n]
+		if err := writeMsg(w, chunk); err != nil {
+			return
+		}
+		answer = answer[n:]
+	}
+}
+
+func soaWithSerial(soa dns.RR, serial uint32) dns.RR {
+	rr := dns.Copy(soa).(*dns.SOA)
+	rr.Serial = serial
+	return rr
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/miekg/dns"
+)
+
+// ゾーン転送のテスト用のdns.ResponseWriter
+// TCPで受けたものとして、書き込まれたメッセージを全て保持する
+type testTransferWriter struct {
+	dns.ResponseWriter
+	remote net.Addr
+	msgs   []*dns.Msg
+}
+
+func (w *testTransferWriter) WriteMsg(m *dns.Msg) error {
+	w.msgs = append(w.msgs, m)
+	return nil
+}
+
+func (w *testTransferWriter) RemoteAddr() net.Addr {
+	return w.remote
+}
+
+// 応答のAnswerを繋げて返す
+func (w *testTransferWriter) answer() []dns.RR {
+	rrs := []dns.RR{}
+	for _, m := range w.msgs {
+		rrs = append(rrs, m.Answer...)
+	}
+	return rrs
+}
+
+func transfer(t *testing.T, remote net.Addr, qtype uint16, serial uint32) *testTransferWriter {
+	t.Helper()
+	r := new(dns.Msg)
+	r.SetQuestion(dnsConf.Zone, qtype)
+	if qtype == dns.TypeIXFR {
+		r.Ns = []dns.RR{&dns.SOA{
+			Hdr:    dns.RR_Header{Name: dnsConf.Zone, Rrtype: dns.TypeSOA, Class: dns.ClassINET},
+			Ns:     dnsConf.Nameservers[0].Name,
+			Mbox:   dnsConf.SOAMbox,
+			Serial: serial,
+		}}
+	}
+	w := &testTransferWriter{remote: remote}
+	handle(w, r)
+	if len(w.msgs) == 0 {
+		t.Fatalf("応答が無い")
+	}
+	return w
+}
+
+func TestZoneTransfer(t *testing.T) {
+	allowed := &net.TCPAddr{IP: net.ParseIP("192.0.2.13"), Port: 53}
+	conf := *dnsConf
+	conf.TransferAllow = []netip.Prefix{netip.MustParsePrefix("192.0.2.13/32")}
+	original := dnsConf
+	dnsConf = &conf
+	t.Cleanup(func() {
+		dnsConf = original
+		resetSubdomains()
+	})
+	resetSubdomains()
+
+	t.Run("許可されていない送信元は拒否する", func(t *testing.T) {
+		w := transfer(t, &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 53}, dns.TypeAXFR, 0)
+		if w.msgs[0].Rcode != dns.RcodeRefused {
+			t.Errorf("rcode = %s, want REFUSED", dns.RcodeToString[w.msgs[0].Rcode])
+		}
+	})
+
+	t.Run("UDPのAXFRは拒否する", func(t *testing.T) {
+		w := transfer(t, &net.UDPAddr{IP: net.ParseIP("192.0.2.13"), Port: 53}, dns.TypeAXFR, 0)
+		if w.msgs[0].Rcode != dns.RcodeRefused {
+			t.Errorf("rcode = %s, want REFUSED", dns.RcodeToString[w.msgs[0].Rcode])
+		}
+	})
+
+	t.Run("AXFRはSOAで始まりSOAで終わる", func(t *testing.T) {
+		rrs := transfer(t, allowed, dns.TypeAXFR, 0).answer()
+		if len(rrs) != len(zoneRecords())+1 {
+			t.Fatalf("len(answer) = %d, want %d", len(rrs), len(zoneRecords())+1)
+		}
+		if rrs[0].Header().Rrtype != dns.TypeSOA || rrs[len(rrs)-1].Header().Rrtype != dns.TypeSOA {
+			t.Errorf("最初と最後がSOAではない: %s, %s", rrs[0], rrs[len(rrs)-1])
+		}
+	})
+
+	t.Run("IXFRは追加されたレコードだけを返す", func(t *testing.T) {
+		serial := dnsSerial.Load()
+		addSubdomain("ixfruser0.t.isucon.pw.")
+		addSubdomain("ixfruser1.t.isucon.pw.")
+
+		rrs := transfer(t, allowed, dns.TypeIXFR, serial).answer()
+		// SOA(新) SOA(旧) SOA(+1) A SOA(+1) SOA(+2) A SOA(新)
+		if len(rrs) != 8 {
+			t.Fatalf("len(answer) = %d, want 8: %v", len(rrs), rrs)
+		}
+		wantSerials := map[int]uint32{0: serial + 2, 1: serial, 2: serial + 1, 4: serial + 1, 5: serial + 2, 7: serial + 2}
+		for i, want := range wantSerials {
+			soa, ok := rrs[i].(*dns.SOA)
+			if !ok || soa.Serial != want {
+				t.Errorf("answer[%d] = %s, want SOA serial %d", i, rrs[i], want)
+			}
+		}
+		if a, ok := rrs[3].(*dns.A); !ok || a.Hdr.Name != "ixfruser0.t.isucon.pw." {
+			t.Errorf("answer[3] = %s, want A of ixfruser0", rrs[3])
+		}
+	})
+
+	t.Run("最新のシリアルならSOAだけを返す", func(t *testing.T) {
+		rrs := transfer(t, allowed, dns.TypeIXFR, dnsSerial.Load()).answer()
+		if len(rrs) != 1 || rrs[0].Header().Rrtype != dns.TypeSOA {
+			t.Errorf("answer = %v, want single SOA", rrs)
+		}
+	})
+
+	t.Run("差分を追えない場合はAXFRにフォールバックする", func(t *testing.T) {
+		serial := dnsSerial.Load()
+		resetSubdomains()
+		if dnsSerial.Load() == serial {
+			t.Errorf("ゾーンを作り直してもシリアルが変わらない")
+		}
+		rrs := transfer(t, allowed, dns.TypeIXFR, serial).answer()
+		if len(rrs) != len(zoneRecords())+1 {
+			t.Errorf("len(answer) = %d, want %d", len(rrs), len(zoneRecords())+1)
+		}
+	})
+}
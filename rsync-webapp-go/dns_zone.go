@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/miekg/dns"
+)
+
+// CNAMEを辿る最大回数(ループ対策)
+const maxCNAMEChain = 8
+
+// 名前ごとのレコード
+// 一度スナップショットに入れたものは書き換えないこと
+type dnsNode struct {
+	// APPサーバのAレコードを返す名前(ユーザのサブドメインなど)
+	// 明示的なAレコードがある場合はそちらを優先する
+	app bool
+	// 型ごとのレコード
+	rrsets map[uint16][]dns.RR
+}
+
+// ゾーン全体のレコード(名前は小文字のFQDN)
+type dnsZoneData map[string]*dnsNode
+
+// 名前と明示的なレコードからゾーンを組み立てる
+func newZoneData(names [][]string, records []dns.RR) *dnsZoneData {
+	size := len(records)
+	for _, list := range names {
+		size += len(list)
+	}
+	z := make(dnsZoneData, size)
+	z[dnsConf.Zone] = &dnsNode{rrsets: map[uint16][]dns.RR{}}
+	for _, list := range names {
+		for _, name := range list {
+			z.addName(name)
+		}
+	}
+	// ゾーン内のネームサーバにはグルーと同じアドレスを返す
+	for _, ns := range dnsConf.Nameservers {
+		if !dns.IsSubDomain(dnsConf.Zone, ns.Name) {
+			continue
+		}
+		rr, err := newRR(fmt.Sprintf("%s %d IN A %s", ns.Name, dnsConf.NSTTL, ns.Address))
+		if err != nil {
+			log.Printf("failed to create glue record for %s: %+v", ns.Name, err)
+			continue
+		}
+		z.addRecord(rr)
+	}
+	for _, rr := range records {
+		z.addRecord(rr)
+	}
+	return &z
+}
+
+// トップレベルのmapだけコピーする(dnsNodeは共有)
+func (z dnsZoneData) clone(extra int) dnsZoneData {
+	next := make(dnsZoneData, len(z)+extra)
+	for name, node := range z {
+		next[name] = node
+	}
+	return next
+}
+
+// APPサーバを指す名前を追加する
+// 組み立て中のゾーンに対してのみ呼ぶこと
+func (z dnsZoneData) addName(name string) {
+	name = strings.ToLower(name)
+	node := z.copyNode(name)
+	node.app = true
+	z[name] = node
+	z.addEmptyNonTerminals(name)
+}
+
+// 明示的なレコードを追加する
+// 組み立て中のゾーンに対してのみ呼ぶこと
+func (z dnsZoneData) addRecord(rr dns.RR) {
+	name := strings.ToLower(rr.Header().Name)
+	rrtype := rr.Header().Rrtype
+	node := z.copyNode(name)
+	rrset := make([]dns.RR, 0, len(node.rrsets[rrtype])+1)
+	for _, existing := range node.rrsets[rrtype] {
+		if dns.IsDuplicate(existing, rr) {
+			continue
+		}
+		rrset = append(rrset, existing)
+	}
+	node.rrsets[rrtype] = append(rrset, rr)
+	z[name] = node
+	z.addEmptyNonTerminals(name)
+}
+
+// 書き換え用にnodeをコピーする(無ければ作る)
+func (z dnsZoneData) copyNode(name string) *dnsNode {
+	node := &dnsNode{rrsets: map[uint16][]dns.RR{}}
+	if current, ok := z[name]; ok {
+		node.app = current.app
+		for rrtype, rrset := range current.rrsets {
+			node.rrsets[rrtype] = rrset
+		}
+	}
+	return node
+}
+
+// a.b.t.isucon.pw. だけがある場合でも b.t.isucon.pw. はNXDOMAINではなくNODATAにする
+func (z dnsZoneData) addEmptyNonTerminals(name string) {
+	for {
+		i, end := dns.NextLabel(name, 0)
+		if end {
+			return
+		}
+		name = name[i:]
+		if !dns.IsSubDomain(dnsConf.Zone, name) {
+			return
+		}
+		if _, ok := z[name]; ok {
+			return
+		}
+		z[name] = &dnsNode{rrsets: map[uint16][]dns.RR{}}
+	}
+}
+
+func lookupNode(name string) *dnsNode {
+	return (*subdomains.Load())[strings.ToLower(name)]
+}
+
+// nodeに含まれるqtypeのレコード
+// ゾーン頂点のNS/SOAは設定から作る
+func (node *dnsNode) records(name string, qtype uint16) []dns.RR {
+	if strings.EqualFold(name, dnsConf.Zone) {
+		switch qtype {
+		case dns.TypeNS:
+			rrs := make([]dns.RR, 0, len(dnsConf.Nameservers))
+			for _, ns := range dnsConf.Nameservers {
+				rr, err := newRR(fmt.Sprintf("%s %d IN NS %s", dnsConf.Zone, dnsConf.NSTTL, ns.Name))
+				if err != nil {
+					log.Printf("failed to create NS record for %s: %+v", ns.Name, err)
+					continue
+				}
+				rrs = append(rrs, rr)
+			}
+			return rrs
+		case dns.TypeSOA:
+			return []dns.RR{soaRR()}
+		case dns.TypeDNSKEY:
+			if dnsSigner != nil {
+				return dnsSigner.dnskeys()
+			}
+		case dns.TypeNSEC3PARAM:
+			if dnsSigner != nil {
+				return []dns.RR{nsec3ParamRR()}
+			}
+		}
+	}
+	if rrs, ok := node.rrsets[qtype]; ok {
+		return rrs
+	}
+	if qtype == dns.TypeA && node.app {
+		// 名前解決後のAPPサーバー
+		return dnsAppPool.records(name)
+	}
+	return nil
+}
+
+// ANYクエリ用に、nodeの全てのレコードを返す
+func (node *dnsNode) allRecords(name string) []dns.RR {
+	rrs := []dns.RR{}
+	types := []uint16{dns.TypeA}
+	if strings.EqualFold(name, dnsConf.Zone) {
+		types = append(types, dns.TypeNS, dns.TypeSOA, dns.TypeDNSKEY, dns.TypeNSEC3PARAM)
+	}
+	for rrtype := range node.rrsets {
+		if rrtype != dns.TypeA {
+			types = append(types, rrtype)
+		}
+	}
+	for _, rrtype := range types {
+		rrs = append(rrs, node.records(name, rrtype)...)
+	}
+	return rrs
+}
+
+// 質問に対する応答をmに詰める
+// 質問の名前自体がゾーンに無い場合はfalseを返す(応答方針は呼び出し側で決める)
+func answerQuestion(m *dns.Msg, q dns.Question) bool {
+	name := q.Name
+	for i := 0; i < maxCNAMEChain; i++ {
+		node := lookupNode(name)
+		if node == nil {
+			if i == 0 {
+				return false
+			}
+			// CNAMEの先がゾーン内に無い
+			m.Rcode = dns.RcodeNameError
+			m.Ns = []dns.RR{soaRR()}
+			return true
+		}
+
+		var rrs []dns.RR
+		if q.Qtype == dns.TypeANY {
+			rrs = node.allRecords(name)
+		} else {
+			rrs = node.records(name, q.Qtype)
+		}
+		if len(rrs) > 0 {
+			m.Answer = append(m.Answer, rrs...)
+			if q.Qtype == dns.TypeNS {
+				m.Extra = append(m.Extra, glueRecords(rrs)...)
+			}
+			return true
+		}
+
+		// CNAMEがあれば、ゾーン内に限って辿る
+		cnames := node.rrsets[dns.TypeCNAME]
+		if len(cnames) == 0 {
+			break
+		}
+		m.Answer = append(m.Answer, cnames[0])
+		target := cnames[0].(*dns.CNAME).Target
+		if !dns.IsSubDomain(dnsConf.Zone, target) {
+			return true
+		}
+		name = target
+	}
+
+	// 名前はあるが、その型のレコードは無い(NODATA)
+	m.Ns = []dns.RR{soaRR()}
+	return true
+}
+
+// NSレコードに対するグルー
+// ゾーン内のネームサーバのみグルーを付ける
+func glueRecords(nsRRs []dns.RR) []dns.RR {
+	extra := []dns.RR{}
+	for _, rr := range nsRRs {
+		ns, ok := rr.(*dns.NS)
+		if !ok || !dns.IsSubDomain(dnsConf.Zone, ns.Ns) {
+			continue
+		}
+		if node := lookupNode(ns.Ns); node != nil {
+			extra = append(extra, node.records(ns.Ns, dns.TypeA)...)
+			extra = append(extra, node.records(ns.Ns, dns.TypeAAAA)...)
+		}
+	}
+	return extra
+}
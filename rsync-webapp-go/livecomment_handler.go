@@ -345,6 +345,9 @@ func postLivecommentHandler(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to fill livecomment: "+err.Error())
 	}
+	if 0 < livecomment.Tip {
+		tagTrending.add(time.Unix(now, 0), livecomment.Livestream.Tags, tagActivity{Tips: livecomment.Tip})
+	}
 
 	return c.JSON(http.StatusCreated, livecomment)
 }
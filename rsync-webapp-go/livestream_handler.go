@@ -1,14 +1,18 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo-contrib/session"
 	"github.com/labstack/echo/v4"
 )
@@ -103,90 +107,43 @@ func reserveLivestreamHandler(c echo.Context) error {
 	// existence already checked
 	userID := sess.Values[defaultUserIDKey].(int64)
 
-	var req *ReserveLivestreamRequest
+	// 値に読み込むので、nullのボディでもreqはnilにならない
+	var req ReserveLivestreamRequest
 	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "failed to decode the request body as json")
 	}
-
-	tx, err := dbConn.BeginTxx(ctx, nil)
+	// 予約枠やライブ配信を書き込む前に、タグや予約期間を確認する
+	ptrTags, err := req.validate()
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to begin transaction: "+err.Error())
+		return err
 	}
-	defer tx.Rollback()
 
-	// 2023/11/25 10:00からの１年間の期間内であるかチェック
-	var (
-		termStartAt    = time.Date(2023, 11, 25, 1, 0, 0, 0, time.UTC)
-		termEndAt      = time.Date(2024, 11, 25, 1, 0, 0, 0, time.UTC)
-		reserveStartAt = time.Unix(req.StartAt, 0)
-		reserveEndAt   = time.Unix(req.EndAt, 0)
-	)
-	if (reserveStartAt.Equal(termEndAt) || reserveStartAt.After(termEndAt)) || (reserveEndAt.Equal(termStartAt) || reserveEndAt.Before(termStartAt)) {
-		return echo.NewHTTPError(http.StatusBadRequest, "bad reservation time range")
-	}
-
-	// 予約枠をみて、予約が可能か調べる
-	// NOTE: 並列な予約のoverbooking防止にFOR UPDATEが必要
-	type SlotCount struct {
-		Count int64 `db:"cnt"`
-	}
-	slotCount := SlotCount{}
-	if err := tx.GetContext(ctx, &slotCount, "SELECT count(1) as cnt FROM reservation_slots WHERE start_at >= ? AND end_at <= ? AND slot = 0 FOR UPDATE", req.StartAt, req.EndAt); err != nil {
-		c.Logger().Warnf("予約枠一覧取得でエラー発生: %+v", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get reservation_slots: "+err.Error())
-	}
-	if 0 < slotCount.Count {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("予約期間 %d ~ %dに対して、予約区間 %d ~ %dが予約できません", termStartAt.Unix(), termEndAt.Unix(), req.StartAt, req.EndAt))
-	}
-
-	var (
-		livestreamModel = &LivestreamModel{
-			UserID:       int64(userID),
-			Title:        req.Title,
-			Description:  req.Description,
-			PlaylistUrl:  req.PlaylistUrl,
-			ThumbnailUrl: req.ThumbnailUrl,
-			StartAt:      req.StartAt,
-			EndAt:        req.EndAt,
-		}
-	)
-
-	if _, err := tx.ExecContext(ctx, "UPDATE reservation_slots SET slot = slot - 1 WHERE start_at >= ? AND end_at <= ?", req.StartAt, req.EndAt); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to update reservation_slot: "+err.Error())
-	}
-	if err := tx.Commit(); err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to commit: "+err.Error())
+	livestreamModel := &LivestreamModel{
+		UserID:       int64(userID),
+		Title:        req.Title,
+		Description:  req.Description,
+		PlaylistUrl:  req.PlaylistUrl,
+		ThumbnailUrl: req.ThumbnailUrl,
+		StartAt:      req.StartAt,
+		EndAt:        req.EndAt,
 	}
-
-	rs, err := dbConn.NamedExecContext(ctx, "INSERT INTO livestreams (user_id, title, description, playlist_url, thumbnail_url, start_at, end_at) VALUES(:user_id, :title, :description, :playlist_url, :thumbnail_url, :start_at, :end_at)", livestreamModel)
-	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to insert livestream: "+err.Error())
+	err = reserveLivestream(ctx, dbConn, livestreamModel, ptrTags)
+	if errors.Is(err, errReservationSlotFull) {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("予約期間 %d ~ %dに対して、予約区間 %d ~ %dが予約できません", reservationTermStartAt.Unix(), reservationTermEndAt.Unix(), req.StartAt, req.EndAt))
 	}
-
-	livestreamID, err := rs.LastInsertId()
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get last inserted livestream id: "+err.Error())
+		c.Logger().Warnf("予約でエラー発生: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
-	livestreamModel.ID = livestreamID
+	livestreamID := livestreamModel.ID
 
-	// タグ追加
-	ptrTags := make([]*Tag, len(req.Tags))
-	tags := make([]Tag, len(req.Tags))
-	insertTags := make([]LivestreamTagModel2, len(req.Tags))
-	for i, tagID := range req.Tags {
-		ptrTags[i] = getPtrTagByID(tagID)
-		tags[i] = *(ptrTags[i])
-		insertTags[i] = LivestreamTagModel2{
-			LivestreamID: livestreamID,
-			TagID:        tagID,
-		}
-	}
-	if 0 < len(insertTags) {
-		if _, err := dbConn.NamedExecContext(ctx, "INSERT INTO livestream_tags (livestream_id, tag_id) VALUES (:livestream_id, :tag_id)", insertTags); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to insert livestream tag: "+err.Error())
-		}
+	// コミットしてからキャッシュに入れる
+	tags := make([]Tag, len(ptrTags))
+	for i, tag := range ptrTags {
+		tags[i] = *tag
 	}
 	livestreamTags.Set(strconv.FormatInt(livestreamID, 10), ptrTags)
+	tagTrending.addLivestream(time.Now(), livestreamID, tags)
 
 	query := `
 select
@@ -241,47 +198,219 @@ where livestreams.id = ?
 	return c.JSON(http.StatusCreated, livestream)
 }
 
+var (
+	errReservationSlotFull = errors.New("reservation slot is full")
+	errLivestreamNotFound  = errors.New("livestream not found")
+	errNotLivestreamOwner  = errors.New("not the owner of the livestream")
+	errLivestreamStarted   = errors.New("livestream has already started")
+)
+
+// 予約枠の確認と消費、ライブ配信とタグの追加を1つのトランザクションで行い、livestreamModel.IDを埋める
+// 区間内に空きの無い予約枠があればerrReservationSlotFullを返す
+func reserveLivestream(ctx context.Context, db *sqlx.DB, livestreamModel *LivestreamModel, tags []*Tag) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := takeReservationSlots(ctx, tx, livestreamModel.StartAt, livestreamModel.EndAt); err != nil {
+		return err
+	}
+
+	rs, err := tx.NamedExecContext(ctx, "INSERT INTO livestreams (user_id, title, description, playlist_url, thumbnail_url, start_at, end_at) VALUES(:user_id, :title, :description, :playlist_url, :thumbnail_url, :start_at, :end_at)", livestreamModel)
+	if err != nil {
+		return fmt.Errorf("failed to insert livestream: %w", err)
+	}
+	livestreamID, err := rs.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last inserted livestream id: %w", err)
+	}
+	if err := insertLivestreamTags(ctx, tx, livestreamID, tags); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	livestreamModel.ID = livestreamID
+	return nil
+}
+
+// 予約の内容(タイトル・説明・URL・タグ・期間)を変えるのを、1つのトランザクションで行う
+// 期間を変える場合は、元の予約枠を返してから新しい予約枠を取る
+func updateLivestream(ctx context.Context, db *sqlx.DB, userID int64, livestreamModel *LivestreamModel, tags []*Tag) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	old, err := lockOwnLivestream(ctx, tx, livestreamModel.ID, userID)
+	if err != nil {
+		return err
+	}
+	if old.StartAt != livestreamModel.StartAt || old.EndAt != livestreamModel.EndAt {
+		if err := refundReservationSlots(ctx, tx, old.StartAt, old.EndAt); err != nil {
+			return err
+		}
+		if err := takeReservationSlots(ctx, tx, livestreamModel.StartAt, livestreamModel.EndAt); err != nil {
+			return err
+		}
+	}
+
+	livestreamModel.UserID = old.UserID
+	if _, err := tx.NamedExecContext(ctx, "UPDATE livestreams SET title = :title, description = :description, playlist_url = :playlist_url, thumbnail_url = :thumbnail_url, start_at = :start_at, end_at = :end_at WHERE id = :id", livestreamModel); err != nil {
+		return fmt.Errorf("failed to update livestream: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM livestream_tags WHERE livestream_id = ?", livestreamModel.ID); err != nil {
+		return fmt.Errorf("failed to delete livestream tags: %w", err)
+	}
+	if err := insertLivestreamTags(ctx, tx, livestreamModel.ID, tags); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit: %w", err)
+	}
+	return nil
+}
+
+// 予約を取り消して予約枠を返すのを、1つのトランザクションで行う
+// 消すのはライブ配信とタグだけで、コメント(チップ)やリアクションなどは統計や支払いの集計に使うので残す
+// タグが外れて盛り上がっているタグの集計から引く分として、窓の中のリアクションとチップを返す
+func cancelLivestream(ctx context.Context, db *sqlx.DB, userID, livestreamID int64) (*tagTrendActivities, error) {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	old, err := lockOwnLivestream(ctx, tx, livestreamID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := refundReservationSlots(ctx, tx, old.StartAt, old.EndAt); err != nil {
+		return nil, err
+	}
+	activities, err := selectTagTrendActivities(ctx, tx, tagTrending.since(time.Now()), livestreamID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get livestream activities: %w", err)
+	}
+	for _, query := range []string{
+		"DELETE FROM livestream_tags WHERE livestream_id = ?",
+		"DELETE FROM livestreams WHERE id = ?",
+	} {
+		if _, err := tx.ExecContext(ctx, query, livestreamID); err != nil {
+			return nil, fmt.Errorf("failed to cancel livestream: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit: %w", err)
+	}
+	return activities, nil
+}
+
+// ライブ配信を排他ロックして、userIDのユーザが配信者であることを確認する
+// 変更・取り消しできるのは開始前のライブ配信だけなので、開始済みならerrLivestreamStartedを返す
+func lockOwnLivestream(ctx context.Context, tx *sqlx.Tx, livestreamID, userID int64) (*LivestreamModel, error) {
+	livestreamModel := LivestreamModel{}
+	err := tx.GetContext(ctx, &livestreamModel, "SELECT * FROM livestreams WHERE id = ? FOR UPDATE", livestreamID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errLivestreamNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get livestream: %w", err)
+	}
+	if livestreamModel.UserID != userID {
+		return nil, errNotLivestreamOwner
+	}
+	if livestreamModel.StartAt <= time.Now().Unix() {
+		return nil, errLivestreamStarted
+	}
+	return &livestreamModel, nil
+}
+
+// 区間内の予約枠を1つずつ取る
+// 並列な予約でoverbookingしないように、区間内の予約枠を全てロックしてから残りを確認する
+func takeReservationSlots(ctx context.Context, tx *sqlx.Tx, startAt, endAt int64) error {
+	var slots []int64
+	if err := tx.SelectContext(ctx, &slots, "SELECT slot FROM reservation_slots WHERE start_at >= ? AND end_at <= ? FOR UPDATE", startAt, endAt); err != nil {
+		return fmt.Errorf("failed to get reservation_slots: %w", err)
+	}
+	if slices.ContainsFunc(slots, func(slot int64) bool { return slot <= 0 }) {
+		return errReservationSlotFull
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE reservation_slots SET slot = slot - 1 WHERE start_at >= ? AND end_at <= ?", startAt, endAt); err != nil {
+		return fmt.Errorf("failed to update reservation_slot: %w", err)
+	}
+	return nil
+}
+
+// 区間内の予約枠を1つずつ返す
+func refundReservationSlots(ctx context.Context, tx *sqlx.Tx, startAt, endAt int64) error {
+	if _, err := tx.ExecContext(ctx, "UPDATE reservation_slots SET slot = slot + 1 WHERE start_at >= ? AND end_at <= ?", startAt, endAt); err != nil {
+		return fmt.Errorf("failed to update reservation_slot: %w", err)
+	}
+	return nil
+}
+
+func insertLivestreamTags(ctx context.Context, tx *sqlx.Tx, livestreamID int64, tags []*Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	insertTags := make([]LivestreamTagModel2, len(tags))
+	for i, tag := range tags {
+		insertTags[i] = LivestreamTagModel2{
+			LivestreamID: livestreamID,
+			TagID:        tag.ID,
+		}
+	}
+	if _, err := tx.NamedExecContext(ctx, "INSERT INTO livestream_tags (livestream_id, tag_id) VALUES (:livestream_id, :tag_id)", insertTags); err != nil {
+		return fmt.Errorf("failed to insert livestream tag: %w", err)
+	}
+	return nil
+}
+
+const (
+	tagMatchAll = "all"
+	tagMatchAny = "any"
+)
+
+// ライブ配信検索API
+// GET /api/livestream/search?tag=A&tag=B&match=all|any&exclude_tag=C&include_children=1&limit=10
+// matchを省略した場合は、指定した全てのタグが付いているもの(all)を返す
+// include_childrenを指定した場合は、親のタグ(カテゴリ)の子孫のタグが付いているものも含める
 func searchLivestreamsHandler(c echo.Context) error {
 	ctx := c.Request().Context()
-	keyTagName := c.QueryParam("tag")
+	tagNames := nonEmptyValues(c.QueryParams()["tag"])
+	excludeTagNames := nonEmptyValues(c.QueryParams()["exclude_tag"])
+	match := c.QueryParam("match")
+	if match == "" {
+		match = tagMatchAll
+	}
+	if match != tagMatchAll && match != tagMatchAny {
+		return echo.NewHTTPError(http.StatusBadRequest, "match query parameter must be all or any")
+	}
 
-	var livestreamModels []*LivestreamModel2
-	if c.QueryParam("tag") != "" {
-		query := `
-with livestream_ids as (
-select livestream_tags.livestream_id
-from tags
-inner join livestream_tags on tags.id = livestream_tags.tag_id
-where tags.name = ?
-)
-select
-  livestreams.id as "livestream_id"
-  , livestreams.title as "livestream_title"
-  , livestreams.description as "livestream_description"
-  , livestreams.playlist_url as "livestream_playlist_url"
-  , livestreams.thumbnail_url as "livestream_thumbnail_url"
-  , livestreams.start_at as "livestream_start_at"
-  , livestreams.end_at as "livestream_end_at"
-  , livestream_owners.id as "livestream_owner_id"
-  , livestream_owners.name as "livestream_owner_name"
-  , livestream_owners.display_name as "livestream_owner_display_name"
-  , livestream_owners.description as "livestream_owner_description"
-  , livestream_owner_themes.id as "livestream_owner_theme_id"
-  , livestream_owner_themes.dark_mode as "livestream_owner_theme_dark_mode"
-from livestreams
-inner join users as livestream_owners on livestream_owners.id = livestreams.user_id
-inner join themes as livestream_owner_themes on livestream_owner_themes.user_id = livestream_owners.id
-where livestreams.id in (select livestream_ids.livestream_id from livestream_ids)
-order by livestream_id desc
-;`
-		if err := dbConn.SelectContext(ctx, &livestreamModels, query, keyTagName); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to get livestreams: "+err.Error())
+	includeChildren := false
+	if v := c.QueryParam("include_children"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "include_children query parameter must be boolean")
 		}
-	} else {
-		// kaizen-04: 1発で取得
-		// // 検索条件なし
-		// query := `SELECT * FROM livestreams ORDER BY id DESC`
-		query := `
+		includeChildren = b
+	}
+
+	conds, args, ok := tagSearchConditions(tagNames, excludeTagNames, match == tagMatchAll, includeChildren)
+	if !ok {
+		// 存在しないタグが指定されたなど、一致するものが無いことが分かっている
+		return c.JSON(http.StatusOK, []Livestream{})
+	}
+
+	// kaizen-04: 1発で取得
+	query := `
 select
   livestreams.id as "livestream_id"
   , livestreams.title as "livestream_title"
@@ -299,21 +428,31 @@ select
 from livestreams
 inner join users as livestream_owners on livestream_owners.id = livestreams.user_id
 inner join themes as livestream_owner_themes on livestream_owner_themes.user_id = livestream_owners.id
-order by livestream_id desc
 `
-		if c.QueryParam("limit") != "" {
-			limit, err := strconv.Atoi(c.QueryParam("limit"))
-			if err != nil {
-				return echo.NewHTTPError(http.StatusBadRequest, "limit query parameter must be integer")
-			}
-			query += fmt.Sprintf(" LIMIT %d", limit)
+	if 0 < len(conds) {
+		query += "where " + strings.Join(conds, "\nand ") + "\n"
+	}
+	query += "order by livestream_id desc\n"
+	// タグで絞り込む場合はlimitを使わない(以前の1タグ検索と同じ)
+	if len(tagNames) == 0 && c.QueryParam("limit") != "" {
+		limit, err := strconv.Atoi(c.QueryParam("limit"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "limit query parameter must be integer")
 		}
-
-		if err := dbConn.SelectContext(ctx, &livestreamModels, query); err != nil {
-			return echo.NewHTTPError(http.StatusInternalServerError, "failed to get livestreams: "+err.Error())
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+	if 0 < len(args) {
+		var err error
+		if query, args, err = sqlx.In(query, args...); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, "failed to build query: "+err.Error())
 		}
 	}
 
+	var livestreamModels []*LivestreamModel2
+	if err := dbConn.SelectContext(ctx, &livestreamModels, query, args...); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get livestreams: "+err.Error())
+	}
+
 	livestreams := make([]Livestream, len(livestreamModels))
 	for i := range livestreamModels {
 		var tags []Tag
@@ -348,6 +487,17 @@ order by livestream_id desc
 	return c.JSON(http.StatusOK, livestreams)
 }
 
+// 空文字列を除いたクエリパラメータの値
+func nonEmptyValues(values []string) []string {
+	list := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
 func getMyLivestreamsHandler(c echo.Context) error {
 	ctx := c.Request().Context()
 	if err := verifyUserSession(c); err != nil {
@@ -558,6 +708,18 @@ func getLivestreamHandler(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "livestream_id in path must be integer")
 	}
 
+	livestream, err := queryLivestreamById(ctx, int64(livestreamID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusNotFound, "not found livestream that has the given id")
+	}
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get livestream: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, livestream)
+}
+
+func queryLivestreamById(ctx context.Context, livestreamID int64) (Livestream, error) {
 	query := `
 select
   livestreams.id as "livestream_id"
@@ -579,16 +741,12 @@ inner join themes as livestream_owner_themes on livestream_owner_themes.user_id
 where livestreams.id = ?
 `
 	livestreamModel := LivestreamModel2{}
-	err = dbConn.GetContext(ctx, &livestreamModel, query, livestreamID)
-	if errors.Is(err, sql.ErrNoRows) {
-		return echo.NewHTTPError(http.StatusNotFound, "not found livestream that has the given id")
-	}
-	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get livestream: "+err.Error())
+	if err := dbConn.GetContext(ctx, &livestreamModel, query, livestreamID); err != nil {
+		return Livestream{}, err
 	}
 	tags, err := getLivestreamTags2(ctx, livestreamModel.Livestream_ID)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get livestream tags: "+err.Error())
+		return Livestream{}, err
 	}
 
 	livestream := Livestream{
@@ -613,11 +771,120 @@ where livestreams.id = ?
 		EndAt:        livestreamModel.Livestream_EndAt,
 	}
 
-	return c.JSON(http.StatusOK, livestream)
+	return livestream, nil
 }
 
 // 指定した livestream_id に紐づく
 // 指定したLivestreamは自分のものであること
+
+// 予約したライブ配信の変更API
+// PUT /api/livestream/:livestream_id
+// 予約と同じ形式で、タイトル・説明・URL・タグ・期間を全て指定する
+// 変更できるのは開始前のライブ配信だけ(開始済みなら400)
+func putLivestreamHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+	defer c.Request().Body.Close()
+
+	if err := verifyUserSession(c); err != nil {
+		return err
+	}
+
+	livestreamID, err := strconv.Atoi(c.Param("livestream_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "livestream_id in path must be integer")
+	}
+
+	// error already checked
+	sess, _ := session.Get(defaultSessionIDKey, c)
+	// existence already checked
+	userID := sess.Values[defaultUserIDKey].(int64)
+
+	var req ReserveLivestreamRequest
+	if err := json.NewDecoder(c.Request().Body).Decode(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "failed to decode the request body as json")
+	}
+	ptrTags, err := req.validate()
+	if err != nil {
+		return err
+	}
+
+	livestreamModel := &LivestreamModel{
+		ID:           int64(livestreamID),
+		Title:        req.Title,
+		Description:  req.Description,
+		PlaylistUrl:  req.PlaylistUrl,
+		ThumbnailUrl: req.ThumbnailUrl,
+		StartAt:      req.StartAt,
+		EndAt:        req.EndAt,
+	}
+	if err := updateLivestream(ctx, dbConn, userID, livestreamModel, ptrTags); err != nil {
+		return livestreamUpdateError(c, err, &req)
+	}
+
+	// コミットしてからキャッシュを入れ替える
+	tags := make([]Tag, len(ptrTags))
+	for i, tag := range ptrTags {
+		tags[i] = *tag
+	}
+	livestreamTags.Set(strconv.FormatInt(livestreamModel.ID, 10), ptrTags)
+	tagTrending.updateLivestream(livestreamModel.ID, tags)
+
+	livestream, err := queryLivestreamById(ctx, livestreamModel.ID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get livestream: "+err.Error())
+	}
+	return c.JSON(http.StatusOK, livestream)
+}
+
+// 予約したライブ配信の取り消しAPI
+// DELETE /api/livestream/:livestream_id
+// 取り消せるのは開始前のライブ配信だけ(開始済みなら400)
+func deleteLivestreamHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if err := verifyUserSession(c); err != nil {
+		return err
+	}
+
+	livestreamID, err := strconv.Atoi(c.Param("livestream_id"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "livestream_id in path must be integer")
+	}
+
+	// error already checked
+	sess, _ := session.Get(defaultSessionIDKey, c)
+	// existence already checked
+	userID := sess.Values[defaultUserIDKey].(int64)
+
+	activities, err := cancelLivestream(ctx, dbConn, userID, int64(livestreamID))
+	if err != nil {
+		return livestreamUpdateError(c, err, nil)
+	}
+
+	// コミットしてからキャッシュを消す
+	livestreamTags.Remove(strconv.Itoa(livestreamID))
+	tagTrending.updateLivestream(int64(livestreamID), nil)
+	tagTrending.remove(activities)
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func livestreamUpdateError(c echo.Context, err error, req *ReserveLivestreamRequest) error {
+	switch {
+	case errors.Is(err, errLivestreamNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "not found livestream that has the given id")
+	case errors.Is(err, errNotLivestreamOwner):
+		return echo.NewHTTPError(http.StatusForbidden, "only the owner can change the livestream")
+	case errors.Is(err, errLivestreamStarted):
+		return echo.NewHTTPError(http.StatusBadRequest, "only livestreams that have not started can be changed")
+	case errors.Is(err, errReservationSlotFull):
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("予約期間 %d ~ %dに対して、予約区間 %d ~ %dが予約できません", reservationTermStartAt.Unix(), reservationTermEndAt.Unix(), req.StartAt, req.EndAt))
+	default:
+		c.Logger().Warnf("予約の変更でエラー発生: %+v", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
+
 func getLivecommentReportsHandler(c echo.Context) error {
 	ctx := c.Request().Context()
 
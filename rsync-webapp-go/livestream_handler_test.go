@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// 既存の予約枠と重ならない区間に、1時間ごとの予約枠を作る
+// DBに繋がっていない場合はスキップする
+func setupReservationSlots(t *testing.T, startAt int64, slots []int64) int64 {
+	t.Helper()
+	if dbConn == nil {
+		t.Skip("MySQLに接続していないのでスキップ")
+	}
+	ctx := context.Background()
+	endAt := startAt
+	for _, slot := range slots {
+		if _, err := dbConn.ExecContext(ctx, "INSERT INTO reservation_slots (slot, start_at, end_at) VALUES (?, ?, ?)", slot, endAt, endAt+3600); err != nil {
+			t.Fatal(err)
+		}
+		endAt += 3600
+	}
+	t.Cleanup(func() {
+		dbConn.ExecContext(ctx, "DELETE FROM livestream_tags WHERE livestream_id IN (SELECT id FROM livestreams WHERE start_at >= ? AND end_at <= ?)", startAt, endAt)
+		dbConn.ExecContext(ctx, "DELETE FROM livestreams WHERE start_at >= ? AND end_at <= ?", startAt, endAt)
+		dbConn.ExecContext(ctx, "DELETE FROM reservation_slots WHERE start_at >= ? AND end_at <= ?", startAt, endAt)
+	})
+	return endAt
+}
+
+func reservationSlots(t *testing.T, startAt, endAt int64) []int64 {
+	t.Helper()
+	var slots []int64
+	if err := dbConn.SelectContext(context.Background(), &slots, "SELECT slot FROM reservation_slots WHERE start_at >= ? AND end_at <= ? ORDER BY start_at", startAt, endAt); err != nil {
+		t.Fatal(err)
+	}
+	return slots
+}
+
+// 同時に予約しても予約枠を超えて予約できず、失敗した予約で枠が減らないこと
+func TestReserveLivestreamConcurrent(t *testing.T) {
+	const (
+		startAt   = 4000000000
+		capacity  = 3
+		reservers = 16
+	)
+	endAt := setupReservationSlots(t, startAt, []int64{capacity, capacity + 2})
+	tags := []*Tag{{ID: 1, Name: "ライブ配信"}, {ID: 2, Name: "ゲーム実況"}}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded []int64
+		full      int
+	)
+	for i := 0; i < reservers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			model := &LivestreamModel{UserID: 1, Title: "同時予約", StartAt: startAt, EndAt: endAt}
+			err := reserveLivestream(context.Background(), dbConn, model, tags)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				succeeded = append(succeeded, model.ID)
+			case errors.Is(err, errReservationSlotFull):
+				full++
+			default:
+				t.Errorf("failed to reserve: %+v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(succeeded) != capacity || full != reservers-capacity {
+		t.Errorf("succeeded = %d, full = %d, want %d, %d", len(succeeded), full, capacity, reservers-capacity)
+	}
+	if got := reservationSlots(t, startAt, endAt); len(got) != 2 || got[0] != 0 || got[1] != 2 {
+		t.Errorf("slots = %v, want [0 2]", got)
+	}
+	var livestreams int
+	if err := dbConn.GetContext(context.Background(), &livestreams, "SELECT count(*) FROM livestreams WHERE start_at >= ? AND end_at <= ?", startAt, endAt); err != nil {
+		t.Fatal(err)
+	}
+	if livestreams != capacity {
+		t.Errorf("livestreams = %d, want %d", livestreams, capacity)
+	}
+	for _, id := range succeeded {
+		var count int
+		if err := dbConn.GetContext(context.Background(), &count, "SELECT count(*) FROM livestream_tags WHERE livestream_id = ?", id); err != nil {
+			t.Fatal(err)
+		}
+		if count != len(tags) {
+			t.Errorf("livestream %d has %d tags, want %d", id, count, len(tags))
+		}
+	}
+}
+
+// 区間の一部の予約枠が埋まっていれば、他の予約枠も減らさずライブ配信も作らないこと
+func TestReserveLivestreamSlotFull(t *testing.T) {
+	const startAt = 4000100000
+	endAt := setupReservationSlots(t, startAt, []int64{2, 0, 2})
+
+	model := &LivestreamModel{UserID: 1, Title: "埋まった予約枠", StartAt: startAt, EndAt: endAt}
+	if err := reserveLivestream(context.Background(), dbConn, model, nil); !errors.Is(err, errReservationSlotFull) {
+		t.Fatalf("err = %v, want errReservationSlotFull", err)
+	}
+	if got := reservationSlots(t, startAt, endAt); len(got) != 3 || got[0] != 2 || got[1] != 0 || got[2] != 2 {
+		t.Errorf("slots = %v, want [2 0 2]", got)
+	}
+	if model.ID != 0 {
+		t.Errorf("livestream id = %d, want 0", model.ID)
+	}
+}
+
+// 予約の期間を変えると元の予約枠を返して新しい予約枠を取り、取り消すと予約枠を返すこと
+func TestUpdateAndCancelLivestream(t *testing.T) {
+	ctx := context.Background()
+	const (
+		startAt = 4000200000
+		userID  = 1
+	)
+	endAt := setupReservationSlots(t, startAt, []int64{1, 1, 0})
+	tags := []*Tag{{ID: 1, Name: "ライブ配信"}}
+
+	model := &LivestreamModel{UserID: userID, Title: "変更前", StartAt: startAt, EndAt: startAt + 3600}
+	if err := reserveLivestream(ctx, dbConn, model, tags); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("配信者以外は変更できない", func(t *testing.T) {
+		update := *model
+		if err := updateLivestream(ctx, dbConn, userID+1, &update, tags); !errors.Is(err, errNotLivestreamOwner) {
+			t.Errorf("err = %v, want errNotLivestreamOwner", err)
+		}
+		if _, err := cancelLivestream(ctx, dbConn, userID+1, model.ID); !errors.Is(err, errNotLivestreamOwner) {
+			t.Errorf("err = %v, want errNotLivestreamOwner", err)
+		}
+	})
+
+	t.Run("空きの無い予約枠には変更できない", func(t *testing.T) {
+		update := *model
+		update.EndAt = endAt
+		if err := updateLivestream(ctx, dbConn, userID, &update, tags); !errors.Is(err, errReservationSlotFull) {
+			t.Errorf("err = %v, want errReservationSlotFull", err)
+		}
+		if got := reservationSlots(t, startAt, endAt); got[0] != 0 || got[1] != 1 || got[2] != 0 {
+			t.Errorf("slots = %v, want [0 1 0]", got)
+		}
+	})
+
+	t.Run("期間を変えると予約枠を付け替える", func(t *testing.T) {
+		update := *model
+		update.Title = "変更後"
+		update.StartAt = startAt + 3600
+		update.EndAt = startAt + 7200
+		if err := updateLivestream(ctx, dbConn, userID, &update, []*Tag{{ID: 2, Name: "ゲーム実況"}}); err != nil {
+			t.Fatal(err)
+		}
+		if got := reservationSlots(t, startAt, endAt); got[0] != 1 || got[1] != 0 || got[2] != 0 {
+			t.Errorf("slots = %v, want [1 0 0]", got)
+		}
+		var tagIDs []int64
+		if err := dbConn.SelectContext(ctx, &tagIDs, "SELECT tag_id FROM livestream_tags WHERE livestream_id = ?", model.ID); err != nil {
+			t.Fatal(err)
+		}
+		if len(tagIDs) != 1 || tagIDs[0] != 2 {
+			t.Errorf("tags = %v, want [2]", tagIDs)
+		}
+	})
+
+	t.Run("取り消すと予約枠を返す", func(t *testing.T) {
+		if _, err := dbConn.ExecContext(ctx, "INSERT INTO livecomments (user_id, livestream_id, comment, tip, created_at) VALUES (?, ?, ?, ?, ?)", userID+1, model.ID, "予約中のコメント", 100, startAt-3600); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			dbConn.ExecContext(ctx, "DELETE FROM livecomments WHERE livestream_id = ?", model.ID)
+		})
+		activities, err := cancelLivestream(ctx, dbConn, userID, model.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 盛り上がっているタグの集計から引く分として、付いていたタグのチップを返す
+		if len(activities.Tips) != 1 || activities.Tips[0] != (tagTrendModel{TagID: 2, CreatedAt: startAt - 3600, Count: 100}) {
+			t.Errorf("tips = %+v", activities.Tips)
+		}
+		// チップは支払いの集計に使うので消さない
+		var tips int64
+		if err := dbConn.GetContext(ctx, &tips, "SELECT IFNULL(SUM(tip), 0) FROM livecomments WHERE livestream_id = ?", model.ID); err != nil {
+			t.Fatal(err)
+		}
+		if tips != 100 {
+			t.Errorf("tips = %d, want 100", tips)
+		}
+		if got := reservationSlots(t, startAt, endAt); got[0] != 1 || got[1] != 1 || got[2] != 0 {
+			t.Errorf("slots = %v, want [1 1 0]", got)
+		}
+		if _, err := cancelLivestream(ctx, dbConn, userID, model.ID); !errors.Is(err, errLivestreamNotFound) {
+			t.Errorf("err = %v, want errLivestreamNotFound", err)
+		}
+	})
+}
+
+// 開始済みのライブ配信は、変更も取り消しもできないこと
+func TestUpdateAndCancelStartedLivestream(t *testing.T) {
+	ctx := context.Background()
+	const (
+		startAt = 1000000000
+		userID  = 1
+	)
+	endAt := setupReservationSlots(t, startAt, []int64{1, 1})
+	model := &LivestreamModel{UserID: userID, Title: "開始済み", StartAt: startAt, EndAt: startAt + 3600}
+	if err := reserveLivestream(ctx, dbConn, model, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	update := *model
+	update.EndAt = endAt
+	if err := updateLivestream(ctx, dbConn, userID, &update, nil); !errors.Is(err, errLivestreamStarted) {
+		t.Errorf("err = %v, want errLivestreamStarted", err)
+	}
+	if _, err := cancelLivestream(ctx, dbConn, userID, model.ID); !errors.Is(err, errLivestreamStarted) {
+		t.Errorf("err = %v, want errLivestreamStarted", err)
+	}
+	if got := reservationSlots(t, startAt, endAt); got[0] != 0 || got[1] != 1 {
+		t.Errorf("slots = %v, want [0 1]", got)
+	}
+}
@@ -4,6 +4,8 @@ package main
 // sqlx的な参考: https://jmoiron.github.io/sqlx/
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -11,6 +13,7 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
@@ -130,10 +133,21 @@ func initializeHandler(c echo.Context) error {
 		c.Logger().Warnf("画像クリア失敗 with err=%s", string(out))
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to initialize: "+err.Error())
 	}
-	initializeTagCache()
+	if err := initializeTagCache(c.Request().Context()); err != nil {
+		c.Logger().Warnf("タグ読み込み失敗 with err=%s", err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to initialize: "+err.Error())
+	}
+	if err := tagTrending.load(c.Request().Context(), dbConn, time.Now()); err != nil {
+		c.Logger().Warnf("タグのランキング読み込み失敗 with err=%s", err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to initialize: "+err.Error())
+	}
 
 	// DNSを初期化
 	resetSubdomains()
+	if err := loadUserSubdomains(c.Request().Context()); err != nil {
+		c.Logger().Warnf("ユーザのサブドメイン読み込み失敗 with err=%s", err.Error())
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to initialize: "+err.Error())
+	}
 
 	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
 	return c.JSON(http.StatusOK, InitializeResponse{
@@ -142,7 +156,6 @@ func initializeHandler(c echo.Context) error {
 }
 
 func main() {
-	go startDNS()
 	go func() {
 		log.Println(http.ListenAndServe("localhost:6060", nil))
 	}()
@@ -161,6 +174,8 @@ func main() {
 
 	// top
 	e.GET("/api/tag", getTagHandler)
+	e.GET("/api/tag/trending", getTrendingTagsHandler)
+	e.GET("/api/tag/suggest", getTagSuggestionsHandler)
 	e.GET("/api/user/:username/theme", getStreamerThemeHandler)
 
 	// livestream
@@ -172,6 +187,12 @@ func main() {
 	e.GET("/api/user/:username/livestream", getUserLivestreamsHandler)
 	// get livestream
 	e.GET("/api/livestream/:livestream_id", getLivestreamHandler)
+	// 予約したライブ配信の変更・取り消し
+	e.PUT("/api/livestream/:livestream_id", putLivestreamHandler)
+	e.DELETE("/api/livestream/:livestream_id", deleteLivestreamHandler)
+	// 予約枠の空き状況
+	e.GET("/api/reservation_slots", getReservationSlotsHandler)
+	e.GET("/api/reservation_slots/next", getNextReservationWindowHandler)
 	// get polling livecomment timeline
 	e.GET("/api/livestream/:livestream_id/livecomment", getLivecommentsHandler)
 	// ライブコメント投稿
@@ -210,8 +231,52 @@ func main() {
 	// 課金情報
 	e.GET("/api/payment", GetPaymentResult)
 
+	// 管理用
+	e.POST("/api/admin/dns/reload", reloadDNSHandler)
+	e.GET("/api/admin/dns/rrl", getDNSRRLStatsHandler)
+	e.GET("/api/admin/dns/records", getDNSRecordsHandler)
+	e.POST("/api/admin/dns/records", postDNSRecordHandler)
+	e.DELETE("/api/admin/dns/records", deleteDNSRecordsHandler)
+	e.GET("/api/admin/dns/zone", getDNSZoneHandler)
+	e.GET("/api/admin/dns/metrics", getDNSMetricsHandler)
+	e.GET("/api/admin/dns/pool", getDNSAppPoolHandler)
+	e.GET("/api/admin/dns/querylog", getDNSQueryLogHandler)
+	e.PUT("/api/admin/dns/querylog", putDNSQueryLogHandler)
+	e.GET("/api/admin/tags", getAdminTagsHandler)
+	e.POST("/api/admin/tags", postAdminTagHandler)
+	e.PUT("/api/admin/tags/:tag_id", putAdminTagHandler)
+	e.PUT("/api/admin/tags/:tag_id/parent", putAdminTagParentHandler)
+	e.POST("/api/admin/tags/:tag_id/merge", mergeAdminTagHandler)
+	e.POST("/api/admin/tags/:tag_id/retire", retireAdminTagHandler)
+
+	// PowerDNSのremote backend (launch=remote, remote-connection-string=http:url=http://<webapp>:8080/api/pdns)
+	e.POST("/api/pdns/initialize", powerDNSInitializeHandler)
+	e.GET("/api/pdns/lookup/:qname/:qtype", powerDNSLookupHandler)
+	e.GET("/api/pdns/list/:domain_id/:zonename", powerDNSListHandler)
+	e.GET("/api/pdns/getAllDomainMetadata/:name", powerDNSGetAllDomainMetadataHandler)
+	e.GET("/api/pdns/getDomainMetadata/:name/:kind", powerDNSGetDomainMetadataHandler)
+	e.GET("/api/pdns/getDomainInfo/:name", powerDNSGetDomainInfoHandler)
+	e.GET("/api/pdns/getAllDomains", powerDNSGetAllDomainsHandler)
+
 	e.HTTPErrorHandler = errorResponseHandler
 
+	subdomainAddr, ok := os.LookupEnv(powerDNSSubdomainAddressEnvKey)
+	if !ok {
+		e.Logger.Errorf("environ %s must be provided", powerDNSSubdomainAddressEnvKey)
+		os.Exit(1)
+	}
+	powerDNSSubdomainAddress = subdomainAddr
+
+	// DNSサーバ起動
+	// サブドメインのAレコードはISUCON13_POWERDNS_SUBDOMAIN_ADDRESSを返す
+	conf, err := loadDNSConfig(powerDNSSubdomainAddress)
+	if err != nil {
+		e.Logger.Errorf("failed to load dns config: %v", err)
+		os.Exit(1)
+	}
+	dnsConf = conf
+	go startDNS()
+
 	// DB接続
 	conn, err := connectDB(e.Logger)
 	if err != nil {
@@ -221,12 +286,30 @@ func main() {
 	defer conn.Close()
 	dbConn = conn
 
-	subdomainAddr, ok := os.LookupEnv(powerDNSSubdomainAddressEnvKey)
-	if !ok {
-		e.Logger.Errorf("environ %s must be provided", powerDNSSubdomainAddressEnvKey)
+	// 作成済みのタグも含めて、tagsテーブルからタグを読み込む
+	tagRegistry = NewTagRegistry(dbConn)
+	if err := tagRegistry.Load(context.Background()); err != nil {
+		e.Logger.Errorf("failed to load tags: %v", err)
+		os.Exit(1)
+	}
+
+	// 盛り上がっているタグの集計
+	window, err := loadTagTrendingWindow()
+	if err != nil {
+		e.Logger.Errorf("failed to load tag trending config: %v", err)
+		os.Exit(1)
+	}
+	tagTrending = newTagTrends(window)
+	if err := tagTrending.load(context.Background(), dbConn, time.Now()); err != nil {
+		e.Logger.Errorf("failed to load tag trending: %v", err)
+		os.Exit(1)
+	}
+
+	// 再起動前に登録されたユーザも名前解決できるように、DNSのサブドメインを作り直す
+	if err := loadUserSubdomains(context.Background()); err != nil {
+		e.Logger.Errorf("failed to load user subdomains: %v", err)
 		os.Exit(1)
 	}
-	powerDNSSubdomainAddress = subdomainAddr
 
 	// HTTPサーバ起動
 	listenAddr := net.JoinHostPort("", strconv.Itoa(listenPort))
@@ -238,10 +321,19 @@ func main() {
 
 type ErrorResponse struct {
 	Error string `json:"error"`
+	// 項目ごとの検証エラー(項目名→理由)
+	Fields map[string]string `json:"fields,omitempty"`
 }
 
 func errorResponseHandler(err error, c echo.Context) {
 	c.Logger().Errorf("error at %s: %+v", c.Path(), err)
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		if e := c.JSON(http.StatusBadRequest, &ErrorResponse{Error: err.Error(), Fields: verr.Fields}); e != nil {
+			c.Logger().Errorf("%+v", e)
+		}
+		return
+	}
 	if he, ok := err.(*echo.HTTPError); ok {
 		if e := c.JSON(he.Code, &ErrorResponse{Error: err.Error()}); e != nil {
 			c.Logger().Errorf("%+v", e)
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/miekg/dns"
+)
+
+// PowerDNSのremote backend (HTTPコネクタ) のAPI
+// https://doc.powerdns.com/authoritative/backends/remote.html
+// 組み込みDNSサーバと同じゾーンを返すので、registerHandlerで登録したユーザもMySQLのトリガ無しで名前解決できる
+
+// remote backendで扱うゾーンのID(ゾーンは1つだけ)
+const powerDNSDomainID = 1
+
+// 応答は全て {"result": ...} の形式で、失敗した場合は {"result": false}
+type PowerDNSResponse struct {
+	Result any `json:"result"`
+}
+
+type PowerDNSRecord struct {
+	QType    string `json:"qtype"`
+	QName    string `json:"qname"`
+	Content  string `json:"content"`
+	TTL      uint32 `json:"ttl"`
+	Auth     bool   `json:"auth"`
+	DomainID int    `json:"domain_id"`
+}
+
+type PowerDNSDomainInfo struct {
+	ID             int      `json:"id"`
+	Zone           string   `json:"zone"`
+	Kind           string   `json:"kind"`
+	Serial         uint32   `json:"serial"`
+	NotifiedSerial uint32   `json:"notified_serial"`
+	Masters        []string `json:"masters"`
+}
+
+// PowerDNSからのリクエストか確認する
+// nginx経由のリクエストと区別できるように、ヘッダではなく接続元のアドレスで判定する
+func verifyPowerDNS(c echo.Context) error {
+	if len(dnsConf.PowerDNSBackendAllow) == 0 {
+		return echo.NewHTTPError(http.StatusForbidden, "powerdns backend is disabled")
+	}
+	addrPort, err := netip.ParseAddrPort(c.Request().RemoteAddr)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusForbidden, "failed to parse remote address: "+err.Error())
+	}
+	addr := addrPort.Addr().Unmap()
+	for _, prefix := range dnsConf.PowerDNSBackendAllow {
+		if prefix.Contains(addr) {
+			return nil
+		}
+	}
+	return echo.NewHTTPError(http.StatusForbidden, "powerdns backend is not allowed from "+addr.String())
+}
+
+func toPowerDNSRecord(rr dns.RR) PowerDNSRecord {
+	return PowerDNSRecord{
+		QType:    dns.TypeToString[rr.Header().Rrtype],
+		QName:    rr.Header().Name,
+		Content:  recordData(rr),
+		TTL:      rr.Header().Ttl,
+		Auth:     true,
+		DomainID: powerDNSDomainID,
+	}
+}
+
+func powerDNSDomainInfo() PowerDNSDomainInfo {
+	return PowerDNSDomainInfo{
+		ID:      powerDNSDomainID,
+		Zone:    dnsConf.Zone,
+		Kind:    "native",
+		Serial:  dnsSerial.Load(),
+		Masters: []string{},
+	}
+}
+
+// パスパラメータの名前をFQDNにする
+func powerDNSName(c echo.Context, param string) string {
+	name := c.Param(param)
+	if unescaped, err := url.PathUnescape(name); err == nil {
+		name = unescaped
+	}
+	return dns.Fqdn(name)
+}
+
+func powerDNSResult(c echo.Context, result any) error {
+	return c.JSON(http.StatusOK, &PowerDNSResponse{Result: result})
+}
+
+// POST /api/pdns/initialize
+func powerDNSInitializeHandler(c echo.Context) error {
+	if err := verifyPowerDNS(c); err != nil {
+		return err
+	}
+
+	return powerDNSResult(c, true)
+}
+
+// GET /api/pdns/lookup/:qname/:qtype
+func powerDNSLookupHandler(c echo.Context) error {
+	if err := verifyPowerDNS(c); err != nil {
+		return err
+	}
+
+	qname := powerDNSName(c, "qname")
+	qtype := strings.ToUpper(c.Param("qtype"))
+	if !dns.IsSubDomain(dnsConf.Zone, qname) {
+		return powerDNSResult(c, false)
+	}
+	node := lookupNode(qname)
+	if node == nil {
+		return powerDNSResult(c, false)
+	}
+
+	var rrs []dns.RR
+	if qtype == "ANY" {
+		rrs = node.allRecords(qname)
+	} else {
+		rrtype, ok := dns.StringToType[qtype]
+		if !ok {
+			return powerDNSResult(c, false)
+		}
+		rrs = node.records(qname, rrtype)
+		// CNAMEはPowerDNS側で辿る
+		if len(rrs) == 0 {
+			rrs = node.rrsets[dns.TypeCNAME]
+		}
+	}
+	if len(rrs) == 0 {
+		return powerDNSResult(c, false)
+	}
+
+	records := make([]PowerDNSRecord, len(rrs))
+	for i, rr := range rrs {
+		records[i] = toPowerDNSRecord(rr)
+	}
+	return powerDNSResult(c, records)
+}
+
+// GET /api/pdns/list/:domain_id/:zonename
+func powerDNSListHandler(c echo.Context) error {
+	if err := verifyPowerDNS(c); err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(powerDNSName(c, "zonename"), dnsConf.Zone) {
+		return powerDNSResult(c, false)
+	}
+	rrs := zoneRecords()
+	records := make([]PowerDNSRecord, len(rrs))
+	for i, rr := range rrs {
+		records[i] = toPowerDNSRecord(rr)
+	}
+	return powerDNSResult(c, records)
+}
+
+// GET /api/pdns/getAllDomainMetadata/:name
+func powerDNSGetAllDomainMetadataHandler(c echo.Context) error {
+	if err := verifyPowerDNS(c); err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(powerDNSName(c, "name"), dnsConf.Zone) {
+		return powerDNSResult(c, false)
+	}
+	// ゾーンのメタデータは持たない
+	return powerDNSResult(c, map[string][]string{})
+}
+
+// GET /api/pdns/getDomainMetadata/:name/:kind
+func powerDNSGetDomainMetadataHandler(c echo.Context) error {
+	if err := verifyPowerDNS(c); err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(powerDNSName(c, "name"), dnsConf.Zone) {
+		return powerDNSResult(c, false)
+	}
+	return powerDNSResult(c, []string{})
+}
+
+// GET /api/pdns/getDomainInfo/:name
+func powerDNSGetDomainInfoHandler(c echo.Context) error {
+	if err := verifyPowerDNS(c); err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(powerDNSName(c, "name"), dnsConf.Zone) {
+		return powerDNSResult(c, false)
+	}
+	return powerDNSResult(c, powerDNSDomainInfo())
+}
+
+// GET /api/pdns/getAllDomains?includeDisabled=true
+func powerDNSGetAllDomainsHandler(c echo.Context) error {
+	if err := verifyPowerDNS(c); err != nil {
+		return err
+	}
+
+	return powerDNSResult(c, []PowerDNSDomainInfo{powerDNSDomainInfo()})
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// remote backendのAPIを呼んで、resultを返す
+func callPowerDNS(t *testing.T, handler echo.HandlerFunc, remoteAddr string, params ...string) (int, any) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	names, values := []string{}, []string{}
+	for i := 0; i+1 < len(params); i += 2 {
+		names = append(names, params[i])
+		values = append(values, params[i+1])
+	}
+	c.SetParamNames(names...)
+	c.SetParamValues(values...)
+	if err := handler(c); err != nil {
+		if he, ok := err.(*echo.HTTPError); ok {
+			return he.Code, nil
+		}
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	var res PowerDNSResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("failed to decode response: %+v", err)
+	}
+	return rec.Code, res.Result
+}
+
+func TestPowerDNSBackend(t *testing.T) {
+	conf := *dnsConf
+	conf.PowerDNSBackendAllow = []netip.Prefix{netip.MustParsePrefix("192.0.2.13/32")}
+	original := dnsConf
+	dnsConf = &conf
+	t.Cleanup(func() {
+		dnsConf = original
+		resetSubdomains()
+	})
+	resetSubdomains()
+	addSubdomain("pdnsuser.t.isucon.pw.")
+
+	const allowed = "192.0.2.13:40000"
+
+	tests := []struct {
+		name       string
+		handler    echo.HandlerFunc
+		remoteAddr string
+		params     []string
+		wantStatus int
+		// 期待するresultのレコード数(-1ならresultはfalse)
+		wantRecords int
+	}{
+		{
+			name:        "許可されていない送信元は拒否する",
+			handler:     powerDNSLookupHandler,
+			remoteAddr:  "192.0.2.1:40000",
+			params:      []string{"qname", "pdnsuser.t.isucon.pw.", "qtype", "A"},
+			wantStatus:  http.StatusForbidden,
+			wantRecords: -1,
+		},
+		{
+			name:        "登録したユーザのサブドメインを返す",
+			handler:     powerDNSLookupHandler,
+			remoteAddr:  allowed,
+			params:      []string{"qname", "pdnsuser.t.isucon.pw", "qtype", "ANY"},
+			wantStatus:  http.StatusOK,
+			wantRecords: 1,
+		},
+		{
+			name:        "ゾーン頂点のSOA",
+			handler:     powerDNSLookupHandler,
+			remoteAddr:  allowed,
+			params:      []string{"qname", "t.isucon.pw.", "qtype", "SOA"},
+			wantStatus:  http.StatusOK,
+			wantRecords: 1,
+		},
+		{
+			name:        "存在しない名前はfalse",
+			handler:     powerDNSLookupHandler,
+			remoteAddr:  allowed,
+			params:      []string{"qname", "nosuchuser.t.isucon.pw.", "qtype", "A"},
+			wantStatus:  http.StatusOK,
+			wantRecords: -1,
+		},
+		{
+			name:        "ゾーン全体",
+			handler:     powerDNSListHandler,
+			remoteAddr:  allowed,
+			params:      []string{"domain_id", "1", "zonename", "t.isucon.pw."},
+			wantStatus:  http.StatusOK,
+			wantRecords: len(zoneRecords()),
+		},
+		{
+			name:        "他のゾーンはfalse",
+			handler:     powerDNSGetDomainInfoHandler,
+			remoteAddr:  allowed,
+			params:      []string{"name", "example.com."},
+			wantStatus:  http.StatusOK,
+			wantRecords: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, result := callPowerDNS(t, tt.handler, tt.remoteAddr, tt.params...)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if status != http.StatusOK {
+				return
+			}
+			if tt.wantRecords < 0 {
+				if result != false {
+					t.Errorf("result = %v, want false", result)
+				}
+				return
+			}
+			records, ok := result.([]any)
+			if !ok || len(records) != tt.wantRecords {
+				t.Errorf("result = %v, want %d records", result, tt.wantRecords)
+			}
+		})
+	}
+}
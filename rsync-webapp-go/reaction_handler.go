@@ -259,6 +259,8 @@ where reactions.id = ?
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get tags: "+err.Error())
 	}
+	tagTrending.add(time.Unix(reactionModel.CreatedAt, 0), tags, tagActivity{Reactions: 1})
+
 	reaction := Reaction{
 		ID:        reactionModel2.Reaction_ID,
 		EmojiName: reactionModel2.Reaction_EmojiName,
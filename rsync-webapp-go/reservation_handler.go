@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 一度に返す・探す予約枠の最大の時間数
+const maxReservationWindowHours = 24 * 31
+
+type ReservationSlot struct {
+	StartAt int64 `json:"start_at"`
+	EndAt   int64 `json:"end_at"`
+	// 残りの予約可能数
+	Slot int64 `json:"slot"`
+}
+
+type ReservationWindow struct {
+	StartAt int64             `json:"start_at"`
+	EndAt   int64             `json:"end_at"`
+	Slots   []ReservationSlot `json:"slots"`
+}
+
+func toReservationSlots(models []ReservationSlotModel) []ReservationSlot {
+	slots := make([]ReservationSlot, len(models))
+	for i, model := range models {
+		slots[i] = ReservationSlot{StartAt: model.StartAt, EndAt: model.EndAt, Slot: model.Slot}
+	}
+	return slots
+}
+
+func int64QueryParam(c echo.Context, name string) (int64, bool, error) {
+	v := c.QueryParam(name)
+	if v == "" {
+		return 0, false, nil
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, false, echo.NewHTTPError(http.StatusBadRequest, name+" query parameter must be integer")
+	}
+	return n, true, nil
+}
+
+// 予約枠の空き状況API
+// GET /api/reservation_slots?from=1700874000&to=1700960400
+// fromからtoまで(最大maxReservationWindowHours時間)の1時間ごとの予約枠と、残りの予約可能数を返す
+func getReservationSlotsHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if err := verifyUserSession(c); err != nil {
+		return err
+	}
+
+	from, ok, err := int64QueryParam(c, "from")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, "from query parameter is required")
+	}
+	to, ok, err := int64QueryParam(c, "to")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, "to query parameter is required")
+	}
+	if to <= from {
+		return echo.NewHTTPError(http.StatusBadRequest, "to must be after from")
+	}
+	if to-from > maxReservationWindowHours*3600 {
+		return echo.NewHTTPError(http.StatusBadRequest, "range between from and to must be at most "+strconv.Itoa(maxReservationWindowHours)+" hours")
+	}
+
+	var models []ReservationSlotModel
+	if err := dbConn.SelectContext(ctx, &models, "SELECT * FROM reservation_slots WHERE start_at >= ? AND end_at <= ? ORDER BY start_at", from, to); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get reservation_slots: "+err.Error())
+	}
+
+	return c.JSON(http.StatusOK, toReservationSlots(models))
+}
+
+// 次に予約できる時間帯の検索API
+// GET /api/reservation_slots/next?hours=3&from=1700874000
+// from(省略時は現在時刻、現在時刻が予約できる期間の外なら期間の最初)以降で、hours時間続けて空きのある最初の時間帯を返す
+func getNextReservationWindowHandler(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if err := verifyUserSession(c); err != nil {
+		return err
+	}
+
+	hours, ok, err := int64QueryParam(c, "hours")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		hours = 1
+	}
+	if hours < 1 || maxReservationWindowHours < hours {
+		return echo.NewHTTPError(http.StatusBadRequest, "hours query parameter must be between 1 and "+strconv.Itoa(maxReservationWindowHours))
+	}
+	from, ok, err := int64QueryParam(c, "from")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		from = defaultReservationSearchFrom(time.Now())
+	}
+	if !inReservationTerm(from) {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("from must be between %d and %d", reservationTermStartAt.Unix(), reservationTermEndAt.Unix()))
+	}
+
+	var models []ReservationSlotModel
+	if err := dbConn.SelectContext(ctx, &models, "SELECT * FROM reservation_slots WHERE start_at >= ? ORDER BY start_at", from); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to get reservation_slots: "+err.Error())
+	}
+
+	window, ok := findReservationWindow(models, int(hours))
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "not found free reservation window")
+	}
+	return c.JSON(http.StatusOK, window)
+}
+
+// 空き枠を探し始める時刻
+// 現在時刻が予約できる期間の外なら、期間の最初から探す
+func defaultReservationSearchFrom(now time.Time) int64 {
+	if !inReservationTerm(now.Unix()) {
+		return reservationTermStartAt.Unix()
+	}
+	return now.Unix()
+}
+
+func inReservationTerm(unix int64) bool {
+	return reservationTermStartAt.Unix() <= unix && unix < reservationTermEndAt.Unix()
+}
+
+// start_at順の予約枠から、隙間なく続くhours個の空きのある予約枠を探す
+func findReservationWindow(models []ReservationSlotModel, hours int) (ReservationWindow, bool) {
+	// 空きが続いている予約枠の先頭
+	first := 0
+	for i, model := range models {
+		switch {
+		case model.Slot <= 0:
+			first = i + 1
+			continue
+		case first < i && models[i-1].EndAt != model.StartAt:
+			// 間が空いているので、ここから数え直す
+			first = i
+		}
+		if i-first+1 == hours {
+			window := models[first : i+1]
+			return ReservationWindow{
+				StartAt: window[0].StartAt,
+				EndAt:   window[len(window)-1].EndAt,
+				Slots:   toReservationSlots(window),
+			}, true
+		}
+	}
+	return ReservationWindow{}, false
+}
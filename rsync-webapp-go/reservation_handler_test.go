@@ -0,0 +1,102 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindReservationWindow(t *testing.T) {
+	slot := func(startAt, slot int64) ReservationSlotModel {
+		return ReservationSlotModel{StartAt: startAt, EndAt: startAt + 3600, Slot: slot}
+	}
+	models := []ReservationSlotModel{
+		slot(0, 1),
+		slot(3600, 0),
+		slot(7200, 2),
+		slot(10800, 1),
+		// 間が空いている
+		slot(18000, 1),
+		slot(21600, 1),
+		slot(25200, 3),
+	}
+
+	testCases := []struct {
+		name        string
+		hours       int
+		wantOK      bool
+		wantStartAt int64
+		wantEndAt   int64
+	}{
+		{
+			name:        "1時間",
+			hours:       1,
+			wantOK:      true,
+			wantStartAt: 0,
+			wantEndAt:   3600,
+		},
+		{
+			name:        "埋まった枠を飛ばす",
+			hours:       2,
+			wantOK:      true,
+			wantStartAt: 7200,
+			wantEndAt:   14400,
+		},
+		{
+			name:        "間が空いた枠は続けない",
+			hours:       3,
+			wantOK:      true,
+			wantStartAt: 18000,
+			wantEndAt:   28800,
+		},
+		{
+			name:   "見つからない",
+			hours:  4,
+			wantOK: false,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			window, ok := findReservationWindow(models, tt.hours)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if window.StartAt != tt.wantStartAt || window.EndAt != tt.wantEndAt || len(window.Slots) != tt.hours {
+				t.Errorf("got: %+v, want: %d ~ %d", window, tt.wantStartAt, tt.wantEndAt)
+			}
+		})
+	}
+}
+
+func TestDefaultReservationSearchFrom(t *testing.T) {
+	testCases := []struct {
+		name string
+		now  time.Time
+		want int64
+	}{
+		{
+			name: "予約できる期間の中なら現在時刻",
+			now:  reservationTermStartAt.Add(36 * time.Hour),
+			want: reservationTermStartAt.Add(36 * time.Hour).Unix(),
+		},
+		{
+			name: "予約できる期間の前なら期間の最初",
+			now:  reservationTermStartAt.Add(-time.Hour),
+			want: reservationTermStartAt.Unix(),
+		},
+		{
+			name: "予約できる期間の後なら期間の最初",
+			now:  reservationTermEndAt,
+			want: reservationTermStartAt.Unix(),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := defaultReservationSearchFrom(tt.now); got != tt.want {
+				t.Errorf("got: %d, want: %d", got, tt.want)
+			}
+		})
+	}
+}
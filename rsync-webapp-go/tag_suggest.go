@@ -0,0 +1,130 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// 候補の一致の仕方(小さいほど上位)
+const (
+	tagSuggestExact = iota
+	tagSuggestPrefix
+	tagSuggestSubstring
+	// 入力の文字が順番通りに含まれる
+	tagSuggestFuzzy
+)
+
+type tagSuggestion struct {
+	tag  *Tag
+	kind int
+	// 部分一致なら一致した位置、あいまい一致なら間に挟まった文字数
+	cost   int
+	length int
+}
+
+// 候補検索用に文字列を正規化する
+// NFKCで全角英数・半角カナを揃えてから、小文字にしてカタカナをひらがなに寄せ、空白を除く
+func foldTagText(s string) string {
+	s = norm.NFKC.String(s)
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r):
+			continue
+		case 'ァ' <= r && r <= 'ヶ':
+			// カタカナ→ひらがな(ヵ・ヶはゕ・ゖになる)
+			r -= 'ァ' - 'ぁ'
+		case r == 'ヽ' || r == 'ヾ':
+			r -= 'ヽ' - 'ゝ'
+		default:
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// qに一致する新しい予約に使えるタグを、一致の良い順に最大limit件返す
+// 完全一致→前方一致→部分一致(前で一致するほど上位)→あいまい一致の順で、同じなら短い名前、IDの順
+func (r *TagRegistry) Suggest(q string, limit int) []*Tag {
+	query := []rune(foldTagText(q))
+	if len(query) == 0 {
+		return []*Tag{}
+	}
+
+	r.mu.RLock()
+	suggestions := []tagSuggestion{}
+	for _, tag := range r.tags {
+		if tag.Retired {
+			continue
+		}
+		name := []rune(r.folded[tag.ID])
+		if kind, cost, ok := matchTagText(name, query); ok {
+			suggestions = append(suggestions, tagSuggestion{tag: tag, kind: kind, cost: cost, length: len(name)})
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(suggestions, func(a, b tagSuggestion) int {
+		if c := cmp.Compare(a.kind, b.kind); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.cost, b.cost); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(a.length, b.length); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.tag.ID, b.tag.ID)
+	})
+	if 0 <= limit && limit < len(suggestions) {
+		suggestions = suggestions[:limit]
+	}
+	tags := make([]*Tag, len(suggestions))
+	for i, s := range suggestions {
+		tags[i] = s.tag
+	}
+	return tags
+}
+
+func matchTagText(name, query []rune) (kind int, cost int, ok bool) {
+	if slices.Equal(name, query) {
+		return tagSuggestExact, 0, true
+	}
+	if i := runesIndex(name, query); 0 <= i {
+		if i == 0 {
+			return tagSuggestPrefix, 0, true
+		}
+		return tagSuggestSubstring, i, true
+	}
+
+	// 順番通りに含まれるか(最初に見つかった位置から貪欲に探す)
+	first, last, j := -1, -1, 0
+	for i, r := range name {
+		if j < len(query) && r == query[j] {
+			if first < 0 {
+				first = i
+			}
+			last = i
+			j++
+		}
+	}
+	if j < len(query) {
+		return 0, 0, false
+	}
+	return tagSuggestFuzzy, last - first + 1 - len(query), true
+}
+
+func runesIndex(s, sub []rune) int {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if slices.Equal(s[i:i+len(sub)], sub) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestFoldTagText(t *testing.T) {
+	testCases := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{
+			name: "カタカナはひらがなにする",
+			s:    "ゲーム実況",
+			want: "げーむ実況",
+		},
+		{
+			name: "半角カナ",
+			s:    "ｹﾞｰﾑ",
+			want: "げーむ",
+		},
+		{
+			name: "全角英数は半角の小文字にする",
+			s:    "ＲＰＧ２",
+			want: "rpg2",
+		},
+		{
+			name: "空白は除く",
+			s:    "Q&A セッション",
+			want: "q&aせっしょん",
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := foldTagText(tt.s); got != tt.want {
+				t.Errorf("got: %s, want: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagRegistrySuggest(t *testing.T) {
+	r := NewTagRegistry(nil)
+	r.Reset(defaultTags())
+
+	names := func(tags []*Tag) []string {
+		list := make([]string, len(tags))
+		for i, tag := range tags {
+			list[i] = tag.Name
+		}
+		return list
+	}
+	testCases := []struct {
+		name  string
+		q     string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "ひらがなで前方一致",
+			q:     "げーむ",
+			limit: 10,
+			want:  []string{"ゲーム実況", "ゲーム解説", "新作ゲーム", "対戦ゲーム", "レトロゲーム", "ホラーゲーム", "アクションゲーム"},
+		},
+		{
+			name:  "完全一致が先頭",
+			q:     "ｆｐｓ",
+			limit: 10,
+			want:  []string{"FPS"},
+		},
+		{
+			name:  "あいまい一致",
+			q:     "ゲ実",
+			limit: 10,
+			want:  []string{"ゲーム実況"},
+		},
+		{
+			name:  "limitで切る",
+			q:     "配信",
+			limit: 2,
+			want:  []string{"歌配信", "記念配信"},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(r.Suggest(tt.q, tt.limit)); !slices.Equal(got, tt.want) {
+				t.Errorf("got: %v, want: %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("作ったタグも候補になる", func(t *testing.T) {
+		if _, err := r.GetOrCreate(context.Background(), "ゲーム音楽"); err != nil {
+			t.Fatal(err)
+		}
+		if got := names(r.Suggest("ゲーム音", 10)); !slices.Equal(got, []string{"ゲーム音楽"}) {
+			t.Errorf("got: %v", got)
+		}
+	})
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	tagTrendingWindowEnvKey  = "ISUCON13_TAG_TRENDING_WINDOW"
+	defaultTagTrendingWindow = time.Hour
+
+	// 集計の窓を分割する区間の数
+	tagTrendBuckets = 60
+)
+
+// タグごとの最近の盛り上がり
+// 窓を区間に分けて区間ごとにタグの集計を持ち、古くなった区間は次に使う時に捨てる
+// 予約・リアクション・チップのたびに加算するだけなので、ベンチマーク中もDBを見ない
+type tagTrends struct {
+	window time.Duration
+	// 区間の長さ(秒)
+	bucketSize int64
+
+	mu      sync.Mutex
+	buckets []tagTrendBucket
+}
+
+type tagTrendBucket struct {
+	// 区間の番号(unix時刻/bucketSize)
+	index      int64
+	activities map[int64]*tagActivity
+	// この区間に予約されたライブ配信のタグ(ライブ配信ID→タグID)
+	livestreams map[int64][]int64
+}
+
+type tagActivity struct {
+	Livestreams int64
+	Reactions   int64
+	Tips        int64
+}
+
+type TrendingTag struct {
+	Tag *Tag `json:"tag"`
+	// 新しい配信の数(起動後に予約されたものだけ)
+	Livestreams int64 `json:"livestreams"`
+	Reactions   int64 `json:"reactions"`
+	Tips        int64 `json:"tips"`
+	Score       int64 `json:"score"`
+}
+
+var tagTrending = newTagTrends(defaultTagTrendingWindow)
+
+func newTagTrends(window time.Duration) *tagTrends {
+	return &tagTrends{
+		window:     window,
+		bucketSize: max(1, int64(window/time.Second)/tagTrendBuckets),
+		buckets:    make([]tagTrendBucket, tagTrendBuckets),
+	}
+}
+
+func loadTagTrendingWindow() (time.Duration, error) {
+	v, ok := os.LookupEnv(tagTrendingWindowEnvKey)
+	if !ok {
+		return defaultTagTrendingWindow, nil
+	}
+	window, err := time.ParseDuration(v)
+	if err != nil || window <= 0 {
+		return 0, fmt.Errorf("environment variable '%s' must be a positive duration: %s", tagTrendingWindowEnvKey, v)
+	}
+	return window, nil
+}
+
+// atの時点の区間に、タグごとに加算する
+func (t *tagTrends) add(at time.Time, tags []Tag, activity tagActivity) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b := t.bucketLocked(at.Unix())
+	if b == nil {
+		return
+	}
+	for _, tag := range tags {
+		b.add(tag.ID, activity)
+	}
+}
+
+// 予約したライブ配信を、atの時点の新しい配信の数として数える
+// 予約を変更・取り消した時に付け直せるように、ライブ配信のタグも覚えておく
+func (t *tagTrends) addLivestream(at time.Time, livestreamID int64, tags []Tag) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b := t.bucketLocked(at.Unix())
+	if b == nil {
+		return
+	}
+	b.addLivestream(livestreamID, tags)
+}
+
+// 予約を変更・取り消したライブ配信の、新しい配信の数を付け直す(tagsがnilなら取り消し)
+// 窓の中で予約されたものだけが対象で、それ以外は何もしない
+func (t *tagTrends) updateLivestream(livestreamID int64, tags []Tag) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := range t.buckets {
+		b := &t.buckets[i]
+		old, ok := b.livestreams[livestreamID]
+		if !ok {
+			continue
+		}
+		for _, tagID := range old {
+			b.add(tagID, tagActivity{Livestreams: -1})
+		}
+		delete(b.livestreams, livestreamID)
+		if tags != nil {
+			b.addLivestream(livestreamID, tags)
+		}
+		return
+	}
+}
+
+// タグの統合に合わせて、srcの集計をdstに付け替える
+// 両方のタグが付いていたライブ配信は統合後にdstだけになるので、新しい配信の数は二重に数えない
+// リアクションとチップはライブ配信ごとには持っていないので、そのまま足す
+func (t *tagTrends) merge(srcID, dstID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i := range t.buckets {
+		b := &t.buckets[i]
+		for livestreamID, tagIDs := range b.livestreams {
+			j := slices.Index(tagIDs, srcID)
+			if j < 0 {
+				continue
+			}
+			if slices.Contains(tagIDs, dstID) {
+				b.add(srcID, tagActivity{Livestreams: -1})
+				b.livestreams[livestreamID] = slices.Delete(tagIDs, j, j+1)
+			} else {
+				tagIDs[j] = dstID
+			}
+		}
+		if a, ok := b.activities[srcID]; ok {
+			b.add(dstID, *a)
+			delete(b.activities, srcID)
+		}
+	}
+}
+
+// unixの時点の区間
+// 既に新しい区間で使われている場合は、窓から外れた古いものなのでnilを返す
+func (t *tagTrends) bucketLocked(unix int64) *tagTrendBucket {
+	index := unix / t.bucketSize
+	b := &t.buckets[index%tagTrendBuckets]
+	if b.index != index || b.activities == nil {
+		if b.index > index && b.activities != nil {
+			return nil
+		}
+		// 窓から外れた古い区間を使い回す
+		b.index = index
+		b.activities = map[int64]*tagActivity{}
+		b.livestreams = map[int64][]int64{}
+	}
+	return b
+}
+
+func (b *tagTrendBucket) add(tagID int64, activity tagActivity) {
+	a, ok := b.activities[tagID]
+	if !ok {
+		a = &tagActivity{}
+		b.activities[tagID] = a
+	}
+	a.Livestreams += activity.Livestreams
+	a.Reactions += activity.Reactions
+	a.Tips += activity.Tips
+}
+
+func (b *tagTrendBucket) addLivestream(livestreamID int64, tags []Tag) {
+	tagIDs := make([]int64, len(tags))
+	for i, tag := range tags {
+		b.add(tag.ID, tagActivity{Livestreams: 1})
+		tagIDs[i] = tag.ID
+	}
+	b.livestreams[livestreamID] = tagIDs
+}
+
+// 窓の中で盛り上がっている順のタグ
+// スコアは統計情報と同じリアクション数+チップの合計に、新しい配信の数を足したもの
+// 使えなくなったタグや消えたタグ、予約の取り消しで何も無くなったタグは含めない
+func (t *tagTrends) ranking(now time.Time, limit int) []TrendingTag {
+	current := now.Unix() / t.bucketSize
+	totals := map[int64]*tagActivity{}
+	t.mu.Lock()
+	for _, b := range t.buckets {
+		if b.activities == nil || b.index <= current-tagTrendBuckets || current < b.index {
+			continue
+		}
+		for tagID, a := range b.activities {
+			total, ok := totals[tagID]
+			if !ok {
+				total = &tagActivity{}
+				totals[tagID] = total
+			}
+			total.Livestreams += a.Livestreams
+			total.Reactions += a.Reactions
+			total.Tips += a.Tips
+		}
+	}
+	t.mu.Unlock()
+
+	ranking := make([]TrendingTag, 0, len(totals))
+	for tagID, a := range totals {
+		tag, ok := tagRegistry.ByID(tagID)
+		if !ok || tag.Retired || *a == (tagActivity{}) {
+			continue
+		}
+		ranking = append(ranking, TrendingTag{
+			Tag:         tag,
+			Livestreams: a.Livestreams,
+			Reactions:   a.Reactions,
+			Tips:        a.Tips,
+			Score:       a.Livestreams + a.Reactions + a.Tips,
+		})
+	}
+	slices.SortFunc(ranking, func(a, b TrendingTag) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Tag.ID, b.Tag.ID)
+	})
+	if 0 <= limit && limit < len(ranking) {
+		ranking = ranking[:limit]
+	}
+	return ranking
+}
+
+type tagTrendModel struct {
+	TagID     int64 `db:"tag_id"`
+	CreatedAt int64 `db:"created_at"`
+	Count     int64 `db:"count"`
+}
+
+// タグ・時刻ごとのリアクション数とチップの合計
+type tagTrendActivities struct {
+	Reactions []tagTrendModel
+	Tips      []tagTrendModel
+}
+
+// since以降のリアクションとチップを、ライブ配信に付いているタグごとに集計する
+// livestreamIDが0でなければ、そのライブ配信の分だけを集計する
+func selectTagTrendActivities(ctx context.Context, db sqlx.QueryerContext, since, livestreamID int64) (*tagTrendActivities, error) {
+	var activities tagTrendActivities
+	args := []any{since}
+	reactionsFilter, tipsFilter := "", ""
+	if livestreamID != 0 {
+		args = append(args, livestreamID)
+		reactionsFilter = " and reactions.livestream_id = ?"
+		tipsFilter = " and livecomments.livestream_id = ?"
+	}
+	query := `
+select livestream_tags.tag_id, reactions.created_at, count(*) as count
+from reactions
+inner join livestream_tags on livestream_tags.livestream_id = reactions.livestream_id
+where reactions.created_at >= ?` + reactionsFilter + `
+group by livestream_tags.tag_id, reactions.created_at
+`
+	if err := sqlx.SelectContext(ctx, db, &activities.Reactions, query, args...); err != nil {
+		return nil, err
+	}
+	query = `
+select livestream_tags.tag_id, livecomments.created_at, sum(livecomments.tip) as count
+from livecomments
+inner join livestream_tags on livestream_tags.livestream_id = livecomments.livestream_id
+where livecomments.created_at >= ? and livecomments.tip > 0` + tipsFilter + `
+group by livestream_tags.tag_id, livecomments.created_at
+`
+	if err := sqlx.SelectContext(ctx, db, &activities.Tips, query, args...); err != nil {
+		return nil, err
+	}
+	return &activities, nil
+}
+
+// 集計の窓の始まり(unix時刻)
+func (t *tagTrends) since(now time.Time) int64 {
+	return now.Add(-t.window).Unix()
+}
+
+// 窓の中のリアクションとチップをDBから読み込む(起動時と初期化時)
+// livestreamsには予約した時刻が無いので、新しい配信の数はDBから作り直せない
+// 再起動や初期化の後は0から数え直すため、窓が一回りするまでは起動後の予約だけの数になる
+func (t *tagTrends) load(ctx context.Context, db *sqlx.DB, now time.Time) error {
+	activities, err := selectTagTrendActivities(ctx, db, t.since(now), 0)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buckets = make([]tagTrendBucket, tagTrendBuckets)
+	for _, r := range activities.Reactions {
+		if b := t.bucketLocked(r.CreatedAt); b != nil {
+			b.add(r.TagID, tagActivity{Reactions: r.Count})
+		}
+	}
+	for _, r := range activities.Tips {
+		if b := t.bucketLocked(r.CreatedAt); b != nil {
+			b.add(r.TagID, tagActivity{Tips: r.Count})
+		}
+	}
+	return nil
+}
+
+// 取り消したライブ配信のリアクションとチップを引く
+// 既に窓から外れた区間の分は、集計に残っていないので何もしない
+func (t *tagTrends) remove(activities *tagTrendActivities) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sub := func(rows []tagTrendModel, activity func(count int64) tagActivity) {
+		for _, r := range rows {
+			index := r.CreatedAt / t.bucketSize
+			b := &t.buckets[index%tagTrendBuckets]
+			if b.index != index || b.activities == nil {
+				continue
+			}
+			b.add(r.TagID, activity(-r.Count))
+		}
+	}
+	sub(activities.Reactions, func(count int64) tagActivity { return tagActivity{Reactions: count} })
+	sub(activities.Tips, func(count int64) tagActivity { return tagActivity{Tips: count} })
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTagTrending(t *testing.T) {
+	original := tagRegistry
+	tagRegistry = NewTagRegistry(nil)
+	tagRegistry.Reset(defaultTags())
+	t.Cleanup(func() { tagRegistry = original })
+
+	tag := func(id int64) Tag {
+		tag, _ := tagRegistry.ByID(id)
+		return *tag
+	}
+	now := time.Unix(1700000000, 0)
+	trends := newTagTrends(time.Hour)
+	// 窓の外
+	trends.add(now.Add(-2*time.Hour), []Tag{tag(1)}, tagActivity{Reactions: 100})
+	trends.add(now.Add(-30*time.Minute), []Tag{tag(1), tag(2)}, tagActivity{Livestreams: 1})
+	trends.add(now.Add(-10*time.Minute), []Tag{tag(2)}, tagActivity{Reactions: 1})
+	trends.add(now.Add(-10*time.Minute), []Tag{tag(2)}, tagActivity{Reactions: 1})
+	trends.add(now, []Tag{tag(3)}, tagActivity{Tips: 500})
+
+	testCases := []struct {
+		name    string
+		now     time.Time
+		limit   int
+		wantIDs []int64
+		want    []TrendingTag
+	}{
+		{
+			name:    "スコアの高い順",
+			now:     now,
+			limit:   10,
+			wantIDs: []int64{3, 2, 1},
+			want: []TrendingTag{
+				{Livestreams: 0, Reactions: 0, Tips: 500, Score: 500},
+				{Livestreams: 1, Reactions: 2, Tips: 0, Score: 3},
+				{Livestreams: 1, Reactions: 0, Tips: 0, Score: 1},
+			},
+		},
+		{
+			name:    "limitで切る",
+			now:     now,
+			limit:   1,
+			wantIDs: []int64{3},
+			want: []TrendingTag{
+				{Livestreams: 0, Reactions: 0, Tips: 500, Score: 500},
+			},
+		},
+		{
+			name:    "窓から外れたものは数えない",
+			now:     now.Add(40 * time.Minute),
+			limit:   10,
+			wantIDs: []int64{3, 2},
+			want: []TrendingTag{
+				{Livestreams: 0, Reactions: 0, Tips: 500, Score: 500},
+				{Livestreams: 0, Reactions: 2, Tips: 0, Score: 2},
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := trends.ranking(tt.now, tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got: %v, want: %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i].Tag.ID != tt.wantIDs[i] {
+					t.Errorf("got[%d].Tag = %v, want id %d", i, got[i].Tag, tt.wantIDs[i])
+				}
+				got[i].Tag = nil
+				if got[i] != tt.want[i] {
+					t.Errorf("got[%d] = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	t.Run("使えなくなったタグは含めない", func(t *testing.T) {
+		if _, err := tagRegistry.Retire(context.Background(), 3); err != nil {
+			t.Fatal(err)
+		}
+		got := trends.ranking(now, 10)
+		if len(got) != 2 || got[0].Tag.ID != 2 {
+			t.Errorf("got: %v", got)
+		}
+	})
+}
+
+// 予約を変更・取り消すと、新しい配信の数を付け直すこと
+func TestTagTrendingUpdateLivestream(t *testing.T) {
+	original := tagRegistry
+	tagRegistry = NewTagRegistry(nil)
+	tagRegistry.Reset(defaultTags())
+	t.Cleanup(func() { tagRegistry = original })
+
+	now := time.Now()
+	trends := newTagTrends(time.Hour)
+	trends.addLivestream(now, 1, []Tag{{ID: 1}, {ID: 2}})
+	trends.addLivestream(now, 2, []Tag{{ID: 2}})
+
+	trends.updateLivestream(1, []Tag{{ID: 3}})
+	got := trends.ranking(now, 10)
+	if len(got) != 2 || got[0].Tag.ID != 2 || got[0].Livestreams != 1 || got[1].Tag.ID != 3 {
+		t.Errorf("got: %+v", got)
+	}
+
+	trends.updateLivestream(2, nil)
+	got = trends.ranking(now, 10)
+	if len(got) != 1 || got[0].Tag.ID != 3 {
+		t.Errorf("got: %+v", got)
+	}
+
+	// 窓の中で予約されていないライブ配信は何もしない
+	trends.updateLivestream(999, nil)
+	if got := trends.ranking(now, 10); len(got) != 1 {
+		t.Errorf("got: %+v", got)
+	}
+}
+
+// タグを統合すると、srcの集計がdstに付き、両方付いていたライブ配信は二重に数えないこと
+func TestTagTrendingMerge(t *testing.T) {
+	original := tagRegistry
+	tagRegistry = NewTagRegistry(nil)
+	tagRegistry.Reset(defaultTags())
+	t.Cleanup(func() { tagRegistry = original })
+
+	now := time.Now()
+	trends := newTagTrends(time.Hour)
+	trends.addLivestream(now, 1, []Tag{{ID: 3}, {ID: 4}})
+	trends.addLivestream(now, 2, []Tag{{ID: 3}})
+	trends.add(now, []Tag{{ID: 3}}, tagActivity{Reactions: 5, Tips: 100})
+
+	trends.merge(3, 4)
+	got := trends.ranking(now, 10)
+	if len(got) != 1 || got[0].Tag.ID != 4 {
+		t.Fatalf("got: %+v", got)
+	}
+	if want := (TrendingTag{Tag: got[0].Tag, Livestreams: 2, Reactions: 5, Tips: 100, Score: 107}); got[0] != want {
+		t.Errorf("got: %+v, want: %+v", got[0], want)
+	}
+
+	// 統合後に予約を取り消すと、dstから引くこと
+	trends.updateLivestream(2, nil)
+	if got := trends.ranking(now, 10); len(got) != 1 || got[0].Livestreams != 1 {
+		t.Errorf("got: %+v", got)
+	}
+}
+
+// 予約を取り消すと、そのライブ配信のリアクションとチップを引き、窓から外れた分は何もしないこと
+func TestTagTrendingRemove(t *testing.T) {
+	original := tagRegistry
+	tagRegistry = NewTagRegistry(nil)
+	tagRegistry.Reset(defaultTags())
+	t.Cleanup(func() { tagRegistry = original })
+
+	now := time.Now()
+	trends := newTagTrends(time.Hour)
+	trends.add(now, []Tag{{ID: 1}, {ID: 2}}, tagActivity{Reactions: 3, Tips: 500})
+	trends.add(now, []Tag{{ID: 2}}, tagActivity{Reactions: 1})
+
+	trends.remove(&tagTrendActivities{
+		Reactions: []tagTrendModel{
+			{TagID: 1, CreatedAt: now.Unix(), Count: 3},
+			{TagID: 2, CreatedAt: now.Unix(), Count: 3},
+			{TagID: 2, CreatedAt: now.Add(-2 * time.Hour).Unix(), Count: 10},
+		},
+		Tips: []tagTrendModel{
+			{TagID: 1, CreatedAt: now.Unix(), Count: 500},
+			{TagID: 2, CreatedAt: now.Unix(), Count: 500},
+		},
+	})
+	got := trends.ranking(now, 10)
+	if len(got) != 1 || got[0].Tag.ID != 2 {
+		t.Fatalf("got: %+v", got)
+	}
+	if want := (TrendingTag{Tag: got[0].Tag, Reactions: 1, Score: 1}); got[0] != want {
+		t.Errorf("got: %+v, want: %+v", got[0], want)
+	}
+}
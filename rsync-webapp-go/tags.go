@@ -2,25 +2,409 @@ package main
 
 import (
 	"context"
-	"github.com/jmoiron/sqlx"
-	cmap "github.com/orcaman/concurrent-map/v2"
+	"errors"
+	"slices"
 	"strconv"
 	"sync"
-	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+	cmap "github.com/orcaman/concurrent-map/v2"
 )
 
 var (
-	tagsCache      []*Tag
-	tagIdCache     = cmap.New[*Tag]()
-	tagNameCache   = cmap.New[*Tag]()
-	tagMutex       sync.Mutex
-	tagId          atomic.Int64
+	tagRegistry    = NewTagRegistry(nil)
 	livestreamTags = cmap.New[[]*Tag]()
 )
 
-// tagsテーブルの代替
-func initializeTagCache() {
-	tagsCache = []*Tag{
+var (
+	ErrTagNotFound      = errors.New("tag not found")
+	ErrTagAlreadyExists = errors.New("tag already exists")
+	ErrTagCycle         = errors.New("tag cannot be a descendant of itself")
+)
+
+// タグの一覧
+// IDからも名前からも引けるようにして、新しく作ったタグはtagsテーブルにも書き込む
+// 返す*Tagは共有しているので書き換えないこと
+type TagRegistry struct {
+	// nilの場合はDBに書き込まず、メモリ上で採番する(テスト用)
+	db *sqlx.DB
+
+	mu     sync.RWMutex
+	tags   []*Tag
+	byID   map[int64]*Tag
+	byName map[string]*Tag
+	lastID int64
+	// 候補検索用に正規化した名前(タグID→名前)
+	folded map[int64]string
+
+	// 同じ名前のタグを同時に作ってIDが重複しないように、書き込みだけ直列にする
+	// DBへの書き込み中も参照は止めない
+	writeMu sync.Mutex
+}
+
+func NewTagRegistry(db *sqlx.DB) *TagRegistry {
+	r := &TagRegistry{db: db}
+	r.Reset(nil)
+	return r
+}
+
+// タグの一覧を入れ替える
+func (r *TagRegistry) Reset(tags []*Tag) {
+	byID := make(map[int64]*Tag, len(tags))
+	byName := make(map[string]*Tag, len(tags))
+	folded := make(map[int64]string, len(tags))
+	var lastID int64
+	for _, tag := range tags {
+		byID[tag.ID] = tag
+		byName[tag.Name] = tag
+		folded[tag.ID] = foldTagText(tag.Name)
+		lastID = max(lastID, tag.ID)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tags = slices.Clone(tags)
+	r.byID = byID
+	r.byName = byName
+	r.folded = folded
+	r.lastID = lastID
+}
+
+// tagsテーブルから読み込む
+func (r *TagRegistry) Load(ctx context.Context) error {
+	var models []TagModel
+	if err := r.db.SelectContext(ctx, &models, "SELECT * FROM tags ORDER BY id"); err != nil {
+		return err
+	}
+	tags := make([]*Tag, len(models))
+	for i, model := range models {
+		tags[i] = &Tag{ID: model.ID, Name: model.Name, Retired: model.Retired, ParentID: model.ParentID}
+	}
+	r.Reset(tags)
+	return nil
+}
+
+func (r *TagRegistry) ByID(id int64) (*Tag, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tag, ok := r.byID[id]
+	return tag, ok
+}
+
+func (r *TagRegistry) ByName(name string) (*Tag, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tag, ok := r.byName[name]
+	return tag, ok
+}
+
+// ID順の全てのタグ
+func (r *TagRegistry) All() []*Tag {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.tags)
+}
+
+// 新しい予約に使えるタグ
+func (r *TagRegistry) Active() []*Tag {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tags := make([]*Tag, 0, len(r.tags))
+	for _, tag := range r.tags {
+		if !tag.Retired {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// 名前からタグを取得し、無ければ作る
+func (r *TagRegistry) GetOrCreate(ctx context.Context, name string) (*Tag, error) {
+	if tag, ok := r.ByName(name); ok {
+		return tag, nil
+	}
+
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	// ロックを待っている間に作られているかもしれないので、もう一度確認
+	if tag, ok := r.ByName(name); ok {
+		return tag, nil
+	}
+	return r.insert(ctx, name)
+}
+
+// 新しいタグを作る
+func (r *TagRegistry) Create(ctx context.Context, name string) (*Tag, error) {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	if _, ok := r.ByName(name); ok {
+		return nil, ErrTagAlreadyExists
+	}
+	return r.insert(ctx, name)
+}
+
+// writeMuを取った状態で呼ぶこと
+func (r *TagRegistry) insert(ctx context.Context, name string) (*Tag, error) {
+	var id int64
+	if r.db != nil {
+		rs, err := r.db.ExecContext(ctx, "INSERT INTO tags (name) VALUES (?)", name)
+		if err != nil {
+			return nil, err
+		}
+		if id, err = rs.LastInsertId(); err != nil {
+			return nil, err
+		}
+	} else {
+		r.mu.RLock()
+		id = r.lastID + 1
+		r.mu.RUnlock()
+	}
+
+	tag := &Tag{ID: id, Name: name}
+	folded := foldTagText(name)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tags = append(r.tags, tag)
+	r.byID[tag.ID] = tag
+	r.byName[tag.Name] = tag
+	r.folded[tag.ID] = folded
+	r.lastID = max(r.lastID, tag.ID)
+	return tag, nil
+}
+
+// タグの名前を変える
+func (r *TagRegistry) Rename(ctx context.Context, id int64, name string) (*Tag, error) {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	old, ok := r.ByID(id)
+	if !ok {
+		return nil, ErrTagNotFound
+	}
+	if old.Name == name {
+		return old, nil
+	}
+	if _, ok := r.ByName(name); ok {
+		return nil, ErrTagAlreadyExists
+	}
+
+	if r.db != nil {
+		if _, err := r.db.ExecContext(ctx, "UPDATE tags SET name = ? WHERE id = ?", name, id); err != nil {
+			return nil, err
+		}
+	}
+	tag := *old
+	tag.Name = name
+	r.replace(old, &tag)
+	invalidateLivestreamTags(id)
+	return &tag, nil
+}
+
+// 新しい予約に使えなくする
+// 既に付いているライブ配信からは外さない
+func (r *TagRegistry) Retire(ctx context.Context, id int64) (*Tag, error) {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	old, ok := r.ByID(id)
+	if !ok {
+		return nil, ErrTagNotFound
+	}
+	if old.Retired {
+		return old, nil
+	}
+
+	if r.db != nil {
+		if _, err := r.db.ExecContext(ctx, "UPDATE tags SET retired = 1 WHERE id = ?", id); err != nil {
+			return nil, err
+		}
+	}
+	tag := *old
+	tag.Retired = true
+	r.replace(old, &tag)
+	invalidateLivestreamTags(id)
+	return &tag, nil
+}
+
+// srcのタグをdstにまとめて、srcを消す
+// 両方のタグが付いていたライブ配信は、dstだけが付いた状態になる
+// srcの子のタグはdstの子にして、盛り上がりの集計もdstに付け替える
+func (r *TagRegistry) Merge(ctx context.Context, srcID, dstID int64) (*Tag, error) {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	src, ok := r.ByID(srcID)
+	if !ok {
+		return nil, ErrTagNotFound
+	}
+	dst, ok := r.ByID(dstID)
+	if !ok {
+		return nil, ErrTagNotFound
+	}
+	if src == dst {
+		return dst, nil
+	}
+
+	// 付け替える親(タグID→新しい親のタグID)
+	reparent := map[int64]int64{}
+	// dstがsrcの子孫の場合は、循環しないようにdstをsrcの位置に上げる
+	if slices.Contains(r.Descendants(srcID), dstID) {
+		reparent[dstID] = src.ParentID
+	}
+	for _, tag := range r.All() {
+		if tag.ParentID == srcID && tag.ID != dstID {
+			reparent[tag.ID] = dstID
+		}
+	}
+
+	if r.db != nil {
+		if err := mergeTagsInDB(ctx, r.db, srcID, dstID, reparent); err != nil {
+			return nil, err
+		}
+	}
+	r.replace(src, nil)
+	tagTrending.merge(srcID, dstID)
+	invalidated := []int64{srcID}
+	for id, parentID := range reparent {
+		old, _ := r.ByID(id)
+		tag := *old
+		tag.ParentID = parentID
+		r.replace(old, &tag)
+		invalidated = append(invalidated, id)
+	}
+	invalidateLivestreamTags(invalidated...)
+	dst, _ = r.ByID(dstID)
+	return dst, nil
+}
+
+func mergeTagsInDB(ctx context.Context, db *sqlx.DB, srcID, dstID int64, reparent map[int64]int64) error {
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 既にdstが付いているライブ配信からはsrcを外すだけにして、重複させない
+	query := `
+delete src from livestream_tags as src
+inner join livestream_tags as dst on dst.livestream_id = src.livestream_id and dst.tag_id = ?
+where src.tag_id = ?
+`
+	if _, err := tx.ExecContext(ctx, query, dstID, srcID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE livestream_tags SET tag_id = ? WHERE tag_id = ?", dstID, srcID); err != nil {
+		return err
+	}
+	for id, parentID := range reparent {
+		if _, err := tx.ExecContext(ctx, "UPDATE tags SET parent_id = ? WHERE id = ?", parentID, id); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM tags WHERE id = ?", srcID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// 親のタグ(カテゴリ)を変える
+// parentIDが0なら親を外す
+func (r *TagRegistry) SetParent(ctx context.Context, id, parentID int64) (*Tag, error) {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+	old, ok := r.ByID(id)
+	if !ok {
+		return nil, ErrTagNotFound
+	}
+	if parentID != 0 {
+		if _, ok := r.ByID(parentID); !ok {
+			return nil, ErrTagNotFound
+		}
+		if slices.Contains(r.Descendants(id), parentID) {
+			return nil, ErrTagCycle
+		}
+	}
+	if old.ParentID == parentID {
+		return old, nil
+	}
+
+	if r.db != nil {
+		if _, err := r.db.ExecContext(ctx, "UPDATE tags SET parent_id = ? WHERE id = ?", parentID, id); err != nil {
+			return nil, err
+		}
+	}
+	tag := *old
+	tag.ParentID = parentID
+	r.replace(old, &tag)
+	invalidateLivestreamTags(id)
+	return &tag, nil
+}
+
+// idのタグとその子孫のタグのID
+func (r *TagRegistry) Descendants(id int64) []int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	ids := []int64{id}
+	// 子のタグは少ないので、毎回全てのタグを見て辿る
+	for i := 0; i < len(ids); i++ {
+		for _, tag := range r.tags {
+			if tag.ParentID == ids[i] && !slices.Contains(ids, tag.ID) {
+				ids = append(ids, tag.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// 新しい予約に使えるタグを、親子関係の木にする
+// 親が使えないタグは根に置く
+func (r *TagRegistry) Tree() []*TagNode {
+	tags := r.Active()
+	nodes := make(map[int64]*TagNode, len(tags))
+	for _, tag := range tags {
+		nodes[tag.ID] = &TagNode{ID: tag.ID, Name: tag.Name}
+	}
+	roots := []*TagNode{}
+	for _, tag := range tags {
+		if parent, ok := nodes[tag.ParentID]; ok {
+			parent.Children = append(parent.Children, nodes[tag.ID])
+		} else {
+			roots = append(roots, nodes[tag.ID])
+		}
+	}
+	return roots
+}
+
+// oldをtagに差し替える(tagがnilなら消す)
+// 共有している*Tagは書き換えずに、新しく作ったものと入れ替える
+func (r *TagRegistry) replace(old, tag *Tag) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := slices.Index(r.tags, old)
+	delete(r.byID, old.ID)
+	delete(r.byName, old.Name)
+	delete(r.folded, old.ID)
+	if tag == nil {
+		r.tags = slices.Delete(r.tags, i, i+1)
+		return
+	}
+	r.tags[i] = tag
+	r.byID[tag.ID] = tag
+	r.byName[tag.Name] = tag
+	r.folded[tag.ID] = foldTagText(tag.Name)
+}
+
+// タグが付いているライブ配信のキャッシュを消す
+// 次に参照された時にDBから読み直す
+func invalidateLivestreamTags(tagIDs ...int64) {
+	for item := range livestreamTags.IterBuffered() {
+		if slices.ContainsFunc(item.Val, func(tag *Tag) bool { return slices.Contains(tagIDs, tag.ID) }) {
+			livestreamTags.Remove(item.Key)
+		}
+	}
+}
+
+// 初期データのタグ(23-initial_tags.sqlと同じ内容)
+// 起動時と初期化時はtagsテーブルから読み込むので、DBを使わないテストで使う
+func defaultTags() []*Tag {
+	tags := []*Tag{
 		{ID: 1, Name: "ライブ配信"}, {ID: 2, Name: "ゲーム実況"}, {ID: 3, Name: "生放送"}, {ID: 4, Name: "アドバイス"}, {ID: 5, Name: "初心者歓迎"},
 		{ID: 6, Name: "プロゲーマー"}, {ID: 7, Name: "新作ゲーム"}, {ID: 8, Name: "レトロゲーム"}, {ID: 9, Name: "RPG"}, {ID: 10, Name: "FPS"},
 		{ID: 11, Name: "アクションゲーム"}, {ID: 12, Name: "対戦ゲーム"}, {ID: 13, Name: "マルチプレイ"}, {ID: 14, Name: "シングルプレイ"}, {ID: 15, Name: "ゲーム解説"},
@@ -43,39 +427,83 @@ func initializeTagCache() {
 		{ID: 96, Name: "コンサート"}, {ID: 97, Name: "ファンミーティング"}, {ID: 98, Name: "コラボ配信"}, {ID: 99, Name: "記念配信"}, {ID: 100, Name: "生誕祭"},
 		{ID: 101, Name: "周年記念"}, {ID: 102, Name: "サプライズ"}, {ID: 103, Name: "椅子"},
 	}
-	tagIdCache.Clear()
-	tagNameCache.Clear()
-	tagId.Store(103)
-	for _, tag := range tagsCache {
-		tagIdCache.Set(strconv.FormatInt(tag.ID, 10), tag)
-		tagNameCache.Set(tag.Name, tag)
+	for parentID, children := range defaultTagChildren {
+		for _, id := range children {
+			tags[id-1].ParentID = parentID
+		}
 	}
+	return tags
 }
 
-// タグ名からTagを取得する
-// キャッシュにない場合、atomic.AddInt64でIDをインクリメントして新規作成
-// tagNameCacheに存在しない同じTagが同時にgetTagByNameされた場合、IDがむやみにインクリメントされる重複する可能性がある
-// mutexを使って排他制御
-func getPtrTagByName(name string) *Tag {
-	if tag, ok := tagNameCache.Get(name); ok {
-		return tag
+// 初期データのタグのカテゴリ(親のタグID→子のタグID)
+// 23-initial_tags.sqlと合わせること
+var defaultTagChildren = map[int64][]int64{
+	2:  {6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+	22: {23, 24, 25, 26, 27, 28, 29, 30, 31, 96},
+	32: {33, 34, 35, 36, 45},
+	38: {39, 40},
+	76: {77, 78, 79},
+}
+
+// タグ名での検索条件(livestreams.idに対するwhere句とその引数)
+// matchAllなら全てのタグ、そうでなければいずれかのタグが付いているもので、excludeNamesのタグが付いていないもの
+// includeChildrenなら、親のタグ(カテゴリ)を指定した場合に子孫のタグのいずれかが付いていれば一致とする
+// そうでなければ指定したタグそのものが付いているかだけを見る
+// 何も一致しないことが分かっている場合(matchAllで存在しないタグが指定された場合など)はokがfalse
+func tagSearchConditions(names, excludeNames []string, matchAll, includeChildren bool) (conds []string, args []any, ok bool) {
+	const subquery = "livestreams.id in (select livestream_tags.livestream_id from livestream_tags where livestream_tags.tag_id in (?))"
+	tagIDs := func(tag *Tag) []int64 {
+		if includeChildren {
+			return tagRegistry.Descendants(tag.ID)
+		}
+		return []int64{tag.ID}
+	}
+
+	var anyIDs []int64
+	for _, name := range names {
+		tag, found := tagRegistry.ByName(name)
+		if !found {
+			if matchAll {
+				return nil, nil, false
+			}
+			continue
+		}
+		ids := tagIDs(tag)
+		if matchAll {
+			conds = append(conds, subquery)
+			args = append(args, ids)
+		} else {
+			anyIDs = append(anyIDs, ids...)
+		}
+	}
+	if !matchAll && 0 < len(names) {
+		if len(anyIDs) == 0 {
+			return nil, nil, false
+		}
+		conds = append(conds, subquery)
+		args = append(args, anyIDs)
+	}
+
+	var excludeIDs []int64
+	for _, name := range excludeNames {
+		if tag, found := tagRegistry.ByName(name); found {
+			excludeIDs = append(excludeIDs, tagIDs(tag)...)
+		}
 	}
-	// キャッシュにない場合、ロックを取得して、もう解除後はもう一度確認
-	tagMutex.Lock()
-	if ptrTag, ok := tagNameCache.Get(name); ok {
-		return ptrTag
+	if 0 < len(excludeIDs) {
+		conds = append(conds, "livestreams.id not in (select livestream_tags.livestream_id from livestream_tags where livestream_tags.tag_id in (?))")
+		args = append(args, excludeIDs)
 	}
-	defer tagMutex.Unlock()
-	ptrTag := &Tag{ID: tagId.Add(1), Name: name}
-	tagNameCache.Set(name, ptrTag)
-	return ptrTag
+	return conds, args, true
 }
 
-// タグIDからTagを取得
-func getPtrTagByID(id int64) *Tag {
-	if ptrTag, ok := tagIdCache.Get(strconv.FormatInt(id, 10)); ok {
-		return ptrTag
+// タグのキャッシュをtagsテーブルから読み直す
+// init.shでDBを初期化した後に呼ぶので、タグもカテゴリもDBと同じ状態になる
+func initializeTagCache(ctx context.Context) error {
+	if err := tagRegistry.Load(ctx); err != nil {
+		return err
 	}
+	livestreamTags.Clear()
 	return nil
 }
 
@@ -95,7 +523,11 @@ func getLivestreamTags(ctx context.Context, tx *sqlx.Tx, streamID int64) ([]Tag,
 		}
 		storedTags := make([]*Tag, len(tags))
 		for i, tag := range tags {
-			storedTags[i] = getPtrTagByID(tag.ID)
+			if ptrTag, ok := tagRegistry.ByID(tag.ID); ok {
+				storedTags[i] = ptrTag
+			} else {
+				storedTags[i] = &tags[i]
+			}
 		}
 		livestreamTags.Set(strconv.FormatInt(streamID, 10), storedTags)
 		return tags, nil
@@ -116,7 +548,11 @@ func getLivestreamTags2(ctx context.Context, streamID int64) ([]Tag, error) {
 		}
 		storedTags := make([]*Tag, len(tags))
 		for i, tag := range tags {
-			storedTags[i] = getPtrTagByID(tag.ID)
+			if ptrTag, ok := tagRegistry.ByID(tag.ID); ok {
+				storedTags[i] = ptrTag
+			} else {
+				storedTags[i] = &tags[i]
+			}
 		}
 		livestreamTags.Set(strconv.FormatInt(streamID, 10), storedTags)
 		return tags, nil
@@ -1,6 +1,16 @@
 package main
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
 
 func TestGetPtrTagByID(t *testing.T) {
 	testCases := []struct {
@@ -35,10 +45,452 @@ func TestGetPtrTagByID(t *testing.T) {
 	}
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
-			got := getPtrTagByID(tt.id)
+			got, ok := tagRegistry.ByID(tt.id)
+			if !ok {
+				t.Fatalf("tag %d not found", tt.id)
+			}
 			if *got != tt.want {
 				t.Errorf("got: %v, want: %v", *got, tt.want)
 			}
 		})
 	}
 }
+
+func TestTagRegistryGetOrCreate(t *testing.T) {
+	r := NewTagRegistry(nil)
+	r.Reset(defaultTags())
+
+	testCases := []struct {
+		name   string
+		tag    string
+		wantID int64
+	}{
+		{
+			name:   "既存のタグはそのまま返す",
+			tag:    "ゲーム実況",
+			wantID: 2,
+		},
+		{
+			name:   "新しいタグは続きのIDで作る",
+			tag:    "新しいタグ",
+			wantID: 104,
+		},
+		{
+			name:   "作ったタグは名前で引ける",
+			tag:    "新しいタグ",
+			wantID: 104,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.GetOrCreate(context.Background(), tt.tag)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != tt.wantID || got.Name != tt.tag {
+				t.Errorf("got: %v, want: {%d %s}", *got, tt.wantID, tt.tag)
+			}
+			if byID, ok := r.ByID(tt.wantID); !ok || byID != got {
+				t.Errorf("ByID(%d) = %v, want %v", tt.wantID, byID, got)
+			}
+		})
+	}
+}
+
+// 同じ名前のタグを同時に作っても、デッドロックせずIDも重複しないこと
+// go test -race -run TestTagRegistryConcurrentCreate
+func TestTagRegistryConcurrentCreate(t *testing.T) {
+	r := NewTagRegistry(nil)
+	r.Reset(defaultTags())
+
+	const (
+		creators = 32
+		names    = 10
+	)
+	var wg sync.WaitGroup
+	for i := 0; i < creators; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < names; j++ {
+				name := fmt.Sprintf("同時作成%d", (i+j)%names)
+				tag, err := r.GetOrCreate(context.Background(), name)
+				if err != nil {
+					t.Errorf("failed to create tag %s: %+v", name, err)
+					return
+				}
+				if got, ok := r.ByID(tag.ID); !ok || got.Name != name {
+					t.Errorf("ByID(%d) = %v, want %s", tag.ID, got, name)
+				}
+				r.All()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	all := r.All()
+	if len(all) != 103+names {
+		t.Fatalf("len(All()) = %d, want %d", len(all), 103+names)
+	}
+	ids := map[int64]bool{}
+	for _, tag := range all {
+		if ids[tag.ID] {
+			t.Errorf("duplicate tag id: %d", tag.ID)
+		}
+		ids[tag.ID] = true
+		if got, ok := r.ByName(tag.Name); !ok || got != tag {
+			t.Errorf("ByName(%s) = %v, want %v", tag.Name, got, tag)
+		}
+	}
+}
+
+// DBを使っても、同じ名前のタグを同時に作るとtagsには1行だけ入り、全員が同じIDを受け取ること
+func TestTagRegistryConcurrentCreateDB(t *testing.T) {
+	if dbConn == nil {
+		t.Skip("MySQLに接続していないのでスキップ")
+	}
+	ctx := context.Background()
+	r := NewTagRegistry(dbConn)
+	if err := r.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		creators = 16
+		names    = 5
+	)
+	prefix := fmt.Sprintf("同時作成DB%d-", time.Now().UnixNano())
+	t.Cleanup(func() {
+		dbConn.ExecContext(ctx, "DELETE FROM tags WHERE name LIKE ?", prefix+"%")
+	})
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		ids = map[string][]int64{}
+	)
+	for i := 0; i < creators; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < names; j++ {
+				name := fmt.Sprintf("%s%d", prefix, (i+j)%names)
+				tag, err := r.GetOrCreate(ctx, name)
+				if err != nil {
+					t.Errorf("failed to create tag %s: %+v", name, err)
+					return
+				}
+				mu.Lock()
+				ids[name] = append(ids[name], tag.ID)
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	var models []TagModel
+	if err := dbConn.SelectContext(ctx, &models, "SELECT * FROM tags WHERE name LIKE ? ORDER BY id", prefix+"%"); err != nil {
+		t.Fatal(err)
+	}
+	if len(models) != names {
+		t.Fatalf("len(tags) = %d, want %d", len(models), names)
+	}
+	for _, model := range models {
+		got := ids[model.Name]
+		if len(got) != creators {
+			t.Errorf("%s: got %d ids, want %d", model.Name, len(got), creators)
+		}
+		for _, id := range got {
+			if id != model.ID {
+				t.Errorf("%s: id = %d, want %d", model.Name, id, model.ID)
+				break
+			}
+		}
+	}
+}
+
+func TestTagRegistryManage(t *testing.T) {
+	ctx := context.Background()
+	r := NewTagRegistry(nil)
+	r.Reset(defaultTags())
+	t.Cleanup(livestreamTags.Clear)
+
+	cacheTags := func(livestreamID string, ids ...int64) {
+		tags := make([]*Tag, len(ids))
+		for i, id := range ids {
+			tags[i], _ = r.ByID(id)
+		}
+		livestreamTags.Set(livestreamID, tags)
+	}
+	cacheTags("1", 1, 2)
+	cacheTags("2", 3)
+	cacheTags("3", 4, 5)
+
+	t.Run("作成", func(t *testing.T) {
+		if _, err := r.Create(ctx, "ゲーム実況"); !errors.Is(err, ErrTagAlreadyExists) {
+			t.Errorf("err = %v, want ErrTagAlreadyExists", err)
+		}
+		tag, err := r.Create(ctx, "新しいタグ")
+		if err != nil || tag.ID != 104 {
+			t.Errorf("tag = %v, err = %v", tag, err)
+		}
+	})
+
+	t.Run("名前の変更", func(t *testing.T) {
+		old, _ := r.ByID(2)
+		if _, err := r.Rename(ctx, 2, "生放送"); !errors.Is(err, ErrTagAlreadyExists) {
+			t.Errorf("err = %v, want ErrTagAlreadyExists", err)
+		}
+		if _, err := r.Rename(ctx, 999, "なし"); !errors.Is(err, ErrTagNotFound) {
+			t.Errorf("err = %v, want ErrTagNotFound", err)
+		}
+		tag, err := r.Rename(ctx, 2, "ゲーム配信")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, ok := r.ByName("ゲーム配信"); !ok || got != tag {
+			t.Errorf("ByName = %v, want %v", got, tag)
+		}
+		if _, ok := r.ByName("ゲーム実況"); ok {
+			t.Errorf("old name is still registered")
+		}
+		// 共有している*Tagは書き換えない
+		if old.Name != "ゲーム実況" {
+			t.Errorf("old tag is modified: %v", old)
+		}
+		if livestreamTags.Has("1") || !livestreamTags.Has("2") {
+			t.Errorf("livestreamTags = %v", livestreamTags.Keys())
+		}
+	})
+
+	t.Run("廃止", func(t *testing.T) {
+		tag, err := r.Retire(ctx, 3)
+		if err != nil || !tag.Retired {
+			t.Fatalf("tag = %v, err = %v", tag, err)
+		}
+		if got, _ := r.ByID(3); !got.Retired {
+			t.Errorf("ByID(3) = %v, want retired", got)
+		}
+		for _, tag := range r.Active() {
+			if tag.ID == 3 {
+				t.Errorf("Active() contains the retired tag")
+			}
+		}
+		if len(r.All()) != 104 {
+			t.Errorf("len(All()) = %d, want 104", len(r.All()))
+		}
+		if livestreamTags.Has("2") {
+			t.Errorf("livestreamTags = %v", livestreamTags.Keys())
+		}
+	})
+
+	t.Run("統合", func(t *testing.T) {
+		tag, err := r.Merge(ctx, 5, 4)
+		if err != nil || tag.ID != 4 {
+			t.Fatalf("tag = %v, err = %v", tag, err)
+		}
+		if _, ok := r.ByID(5); ok {
+			t.Errorf("merged tag is still registered")
+		}
+		if _, ok := r.ByName("初心者歓迎"); ok {
+			t.Errorf("merged tag name is still registered")
+		}
+		if len(r.All()) != 103 {
+			t.Errorf("len(All()) = %d, want 103", len(r.All()))
+		}
+		if livestreamTags.Has("3") {
+			t.Errorf("livestreamTags = %v", livestreamTags.Keys())
+		}
+		if _, err := r.Merge(ctx, 5, 4); !errors.Is(err, ErrTagNotFound) {
+			t.Errorf("err = %v, want ErrTagNotFound", err)
+		}
+	})
+}
+
+func TestTagSearchConditions(t *testing.T) {
+	original := tagRegistry
+	tagRegistry = NewTagRegistry(nil)
+	tagRegistry.Reset(defaultTags())
+	t.Cleanup(func() { tagRegistry = original })
+
+	const (
+		in    = "livestreams.id in (select livestream_tags.livestream_id from livestream_tags where livestream_tags.tag_id in (?))"
+		notIn = "livestreams.id not in (select livestream_tags.livestream_id from livestream_tags where livestream_tags.tag_id in (?))"
+	)
+	testCases := []struct {
+		name     string
+		names    []string
+		exclude  []string
+		matchAll bool
+		children bool
+		wantOK   bool
+		want     []string
+		wantArgs []any
+	}{
+		{
+			name:   "条件なし",
+			wantOK: true,
+		},
+		{
+			name:     "全てのタグ",
+			names:    []string{"ゲーム実況", "RPG"},
+			matchAll: true,
+			wantOK:   true,
+			want:     []string{in, in},
+			wantArgs: []any{[]int64{2}, []int64{9}},
+		},
+		{
+			name:     "全てのタグで子孫を含める",
+			names:    []string{"ゲーム実況", "RPG"},
+			matchAll: true,
+			children: true,
+			wantOK:   true,
+			want:     []string{in, in},
+			wantArgs: []any{[]int64{2, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, []int64{9}},
+		},
+		{
+			name:     "子のタグは子孫だけに一致する",
+			names:    []string{"お料理配信", "手料理"},
+			matchAll: true,
+			children: true,
+			wantOK:   true,
+			want:     []string{in, in},
+			wantArgs: []any{[]int64{38, 39, 40}, []int64{39}},
+		},
+		{
+			name:     "全てのタグで存在しないタグを含む",
+			names:    []string{"ゲーム実況", "存在しないタグ"},
+			matchAll: true,
+			wantOK:   false,
+		},
+		{
+			name:     "いずれかのタグで存在しないタグは無視する",
+			names:    []string{"ゲーム実況", "存在しないタグ", "RPG"},
+			children: true,
+			wantOK:   true,
+			want:     []string{in},
+			wantArgs: []any{[]int64{2, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 9}},
+		},
+		{
+			name:   "いずれかのタグで全て存在しない",
+			names:  []string{"存在しないタグ"},
+			wantOK: false,
+		},
+		{
+			name:     "除外するタグ",
+			names:    []string{"ゲーム実況"},
+			exclude:  []string{"FPS", "存在しないタグ"},
+			matchAll: true,
+			wantOK:   true,
+			want:     []string{in, notIn},
+			wantArgs: []any{[]int64{2}, []int64{10}},
+		},
+		{
+			name:     "子孫を含めて除外する",
+			names:    []string{"トーク配信"},
+			exclude:  []string{"お料理配信"},
+			matchAll: true,
+			children: true,
+			wantOK:   true,
+			want:     []string{in, notIn},
+			wantArgs: []any{[]int64{32, 33, 34, 35, 36, 45}, []int64{38, 39, 40}},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			conds, args, ok := tagSearchConditions(tt.names, tt.exclude, tt.matchAll, tt.children)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !reflect.DeepEqual(conds, tt.want) || !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("got: %v %v, want: %v %v", conds, args, tt.want, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestTagRegistryHierarchy(t *testing.T) {
+	ctx := context.Background()
+	r := NewTagRegistry(nil)
+	r.Reset(defaultTags())
+	t.Cleanup(livestreamTags.Clear)
+
+	t.Run("木にする", func(t *testing.T) {
+		tree := r.Tree()
+		if len(tree) != 103-31 {
+			t.Fatalf("len(roots) = %d, want %d", len(tree), 103-31)
+		}
+		for _, node := range tree {
+			if node.ID == 38 {
+				if len(node.Children) != 2 || node.Children[0].Name != "手料理" || node.Children[1].Name != "レシピ紹介" {
+					t.Errorf("children = %v", node.Children)
+				}
+				return
+			}
+		}
+		t.Errorf("お料理配信 is not a root")
+	})
+
+	t.Run("循環する親は設定できない", func(t *testing.T) {
+		if _, err := r.SetParent(ctx, 38, 39); !errors.Is(err, ErrTagCycle) {
+			t.Errorf("err = %v, want ErrTagCycle", err)
+		}
+		if _, err := r.SetParent(ctx, 38, 38); !errors.Is(err, ErrTagCycle) {
+			t.Errorf("err = %v, want ErrTagCycle", err)
+		}
+		if _, err := r.SetParent(ctx, 38, 999); !errors.Is(err, ErrTagNotFound) {
+			t.Errorf("err = %v, want ErrTagNotFound", err)
+		}
+	})
+
+	t.Run("親を変える", func(t *testing.T) {
+		tag, err := r.SetParent(ctx, 3, 32)
+		if err != nil || tag.ParentID != 32 {
+			t.Fatalf("tag = %v, err = %v", tag, err)
+		}
+		if !slices.Contains(r.Descendants(32), 3) {
+			t.Errorf("Descendants(32) = %v", r.Descendants(32))
+		}
+	})
+
+	t.Run("統合すると子のタグは統合先の子になる", func(t *testing.T) {
+		if _, err := r.Merge(ctx, 38, 32); err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range []int64{39, 40} {
+			if tag, _ := r.ByID(id); tag.ParentID != 32 {
+				t.Errorf("ByID(%d) = %v, want parent 32", id, tag)
+			}
+		}
+	})
+
+	t.Run("子孫に統合すると統合先が親の位置に上がる", func(t *testing.T) {
+		if _, err := r.SetParent(ctx, 28, 27); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.Merge(ctx, 22, 28); err != nil {
+			t.Fatal(err)
+		}
+		tag, _ := r.ByID(28)
+		if tag.ParentID != 0 {
+			t.Errorf("ByID(28) = %v, want root", tag)
+		}
+		if got := r.Descendants(28); len(got) != 10 {
+			t.Errorf("Descendants(28) = %v", got)
+		}
+		if tag, _ := r.ByID(27); tag.ParentID != 28 {
+			t.Errorf("ByID(27) = %v, want parent 28", tag)
+		}
+	})
+}
+
+// ライブ配信やタグ一覧で返すタグのJSONは、カテゴリを入れてもidとnameだけであること
+func TestTagJSON(t *testing.T) {
+	b, err := json.Marshal(&Tag{ID: 9, Name: "RPG", Retired: true, ParentID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"id":9,"name":"RPG"}`; got != want {
+		t.Errorf("got: %s, want: %s", got, want)
+	}
+}
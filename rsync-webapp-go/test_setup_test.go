@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -20,15 +21,25 @@ func TestMain(m *testing.M) {
 	}
 
 	// DB接続
-	dbConn, err := connectDB(nil)
+	// グローバルのdbConnに入れる(:=だとTestMainのローカル変数になってしまう)
+	// 接続できない場合はdbConnをnilのままにして、DBを使うテストはスキップする
+	conn, err := connectDB(nil)
 	if err != nil {
-		fmt.Printf("DB接続に失敗しました: %+v\n", err)
-		os.Exit(1)
+		fmt.Printf("DB接続に失敗したので、DBを使うテストはスキップします: %+v\n", err)
+	} else {
+		defer conn.Close()
+		dbConn = conn
 	}
-	defer dbConn.Close()
 
 	// タグキャッシュのセットアップ
-	initializeTagCache()
+	// DBが無い場合は初期データと同じタグを使う
+	tagRegistry = NewTagRegistry(dbConn)
+	if dbConn == nil {
+		tagRegistry.Reset(defaultTags())
+	} else if err := initializeTagCache(context.Background()); err != nil {
+		fmt.Printf("タグの読み込みに失敗しました: %+v\n", err)
+		os.Exit(1)
+	}
 
 	// テストの実行
 	code := m.Run()
@@ -4,30 +4,96 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
+// レスポンスのJSONはidとnameだけ(カテゴリや使えるかどうかは管理用APIと?tree=trueで返す)
 type Tag struct {
 	ID   int64  `json:"id"`
 	Name string `json:"name"`
+	// 新しい予約には使えないタグ(既存のライブ配信には付いたまま)
+	Retired bool `json:"-"`
+	// 親のタグ(カテゴリ)のID。無ければ0
+	ParentID int64 `json:"-" db:"parent_id"`
 }
 
 type TagModel struct {
-	ID   int64  `db:"id"`
-	Name string `db:"name"`
+	ID       int64  `db:"id"`
+	Name     string `db:"name"`
+	Retired  bool   `db:"retired"`
+	ParentID int64  `db:"parent_id"`
 }
 
 type TagsResponse struct {
 	Tags []*Tag `json:"tags"`
 }
 
+// 親子関係の木にしたタグ
+type TagNode struct {
+	ID       int64      `json:"id"`
+	Name     string     `json:"name"`
+	Children []*TagNode `json:"children,omitempty"`
+}
+
+type TagTreeResponse struct {
+	Tags []*TagNode `json:"tags"`
+}
+
+// タグ一覧API
+// GET /api/tag?tree=true
+// treeを指定した場合は、親子関係の木にして返す
 func getTagHandler(c echo.Context) error {
+	if tree, _ := strconv.ParseBool(c.QueryParam("tree")); tree {
+		return c.JSON(http.StatusOK, &TagTreeResponse{
+			Tags: tagRegistry.Tree(),
+		})
+	}
+	return c.JSON(http.StatusOK, &TagsResponse{
+		Tags: tagRegistry.Active(),
+	})
+}
+
+// タグの入力候補API
+// GET /api/tag/suggest?q=げーむ&limit=10
+// ひらがな・カタカナ、全角・半角の違いは無視して探す
+func getTagSuggestionsHandler(c echo.Context) error {
+	q := c.QueryParam("q")
+	if strings.TrimSpace(q) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "q query parameter is required")
+	}
+	limit := 10
+	if v := c.QueryParam("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "limit query parameter must be non-negative integer")
+		}
+		limit = n
+	}
 	return c.JSON(http.StatusOK, &TagsResponse{
-		Tags: tagsCache,
+		Tags: tagRegistry.Suggest(q, limit),
 	})
 }
 
+// 盛り上がっているタグのランキングAPI
+// GET /api/tag/trending?limit=10
+// ISUCON13_TAG_TRENDING_WINDOW(デフォルト1h)の間の、新しい配信の数・リアクション数・チップの合計で順位を付ける
+// リアクション数とチップは再起動後もDBから窓の分を数え直すが、新しい配信の数は起動後の予約だけを数える
+func getTrendingTagsHandler(c echo.Context) error {
+	limit := 10
+	if v := c.QueryParam("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "limit query parameter must be non-negative integer")
+		}
+		limit = n
+	}
+	return c.JSON(http.StatusOK, tagTrending.ranking(time.Now(), limit))
+}
+
 // 配信者のテーマ取得API
 // GET /api/user/:username/theme
 func getStreamerThemeHandler(c echo.Context) error {
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// リクエストの項目ごとの検証エラー
+// errorResponseHandlerで400にして、項目名→理由をfieldsに入れて返す
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	keys := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		keys = append(keys, field)
+	}
+	slices.Sort(keys)
+	msgs := make([]string, len(keys))
+	for i, field := range keys {
+		msgs[i] = field + ": " + e.Fields[field]
+	}
+	return "invalid request: " + strings.Join(msgs, ", ")
+}
+
+// 同じ項目に複数の理由がある場合は最初のものを残す
+func (e *ValidationError) add(field, format string, args ...any) {
+	if e.Fields == nil {
+		e.Fields = map[string]string{}
+	}
+	if _, ok := e.Fields[field]; !ok {
+		e.Fields[field] = fmt.Sprintf(format, args...)
+	}
+}
+
+// 検証エラーが無ければnil
+func (e *ValidationError) errOrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+const maxLivestreamTextLength = 255
+
+// 予約できる期間(2023/11/25 10:00からの1年間)
+var (
+	reservationTermStartAt = time.Date(2023, 11, 25, 1, 0, 0, 0, time.UTC)
+	reservationTermEndAt   = time.Date(2024, 11, 25, 1, 0, 0, 0, time.UTC)
+)
+
+// DBに書き込む前に予約リクエストを検証して、付けるタグを返す
+// reqは書き換えないので、検証を通ったらそのまま保存できる
+func (req *ReserveLivestreamRequest) validate() ([]*Tag, error) {
+	verr := &ValidationError{}
+
+	// 空白だけのタイトルは空とみなし、前後の空白は保存前に落とさず検証エラーにする
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		verr.add("title", "title is required")
+	} else if title != req.Title {
+		verr.add("title", "title must not have leading or trailing spaces")
+	} else if utf8.RuneCountInString(req.Title) > maxLivestreamTextLength {
+		verr.add("title", "title must be at most %d characters", maxLivestreamTextLength)
+	}
+	validateURL(verr, "playlist_url", req.PlaylistUrl)
+	validateURL(verr, "thumbnail_url", req.ThumbnailUrl)
+
+	if req.EndAt <= req.StartAt {
+		verr.add("end_at", "end_at must be after start_at")
+	}
+	if !time.Unix(req.StartAt, 0).Before(reservationTermEndAt) {
+		verr.add("start_at", "start_at must be before %d", reservationTermEndAt.Unix())
+	}
+	if !time.Unix(req.EndAt, 0).After(reservationTermStartAt) {
+		verr.add("end_at", "end_at must be after %d", reservationTermStartAt.Unix())
+	}
+
+	tags := make([]*Tag, 0, len(req.Tags))
+	for i, tagID := range req.Tags {
+		field := fmt.Sprintf("tags[%d]", i)
+		tag, ok := tagRegistry.ByID(tagID)
+		switch {
+		case !ok:
+			verr.add(field, "tag %d not found", tagID)
+		case tag.Retired:
+			verr.add(field, "tag %d is retired", tagID)
+		case slices.Contains(req.Tags[:i], tagID):
+			verr.add(field, "tag %d is duplicated", tagID)
+		default:
+			tags = append(tags, tag)
+		}
+	}
+
+	if err := verr.errOrNil(); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// 空でなければhttp(s)の絶対URLであること
+func validateURL(verr *ValidationError, field, s string) {
+	if s == "" {
+		return
+	}
+	if utf8.RuneCountInString(s) > maxLivestreamTextLength {
+		verr.add(field, "%s must be at most %d characters", field, maxLivestreamTextLength)
+		return
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		verr.add(field, "%s must be an http or https url", field)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestReserveLivestreamRequestValidate(t *testing.T) {
+	original := tagRegistry
+	tagRegistry = NewTagRegistry(nil)
+	tagRegistry.Reset(defaultTags())
+	t.Cleanup(func() { tagRegistry = original })
+	if _, err := tagRegistry.Retire(context.Background(), 103); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := func() ReserveLivestreamRequest {
+		return ReserveLivestreamRequest{
+			Tags:         []int64{1, 2},
+			Title:        "ゲーム実況配信",
+			PlaylistUrl:  "https://media.xiidea.net/live/master.m3u8",
+			ThumbnailUrl: "https://media.xiidea.net/isucon12_thumbnail.jpg",
+			StartAt:      1711900800,
+			EndAt:        1711904400,
+		}
+	}
+	testCases := []struct {
+		name   string
+		modify func(req *ReserveLivestreamRequest)
+		want   map[string]string
+	}{
+		{
+			name:   "正しいリクエスト",
+			modify: func(req *ReserveLivestreamRequest) {},
+		},
+		{
+			name: "存在しない・重複した・使えないタグ",
+			modify: func(req *ReserveLivestreamRequest) {
+				req.Tags = []int64{1, 999, 1, 103}
+			},
+			want: map[string]string{
+				"tags[1]": "tag 999 not found",
+				"tags[2]": "tag 1 is duplicated",
+				"tags[3]": "tag 103 is retired",
+			},
+		},
+		{
+			name: "タイトルが空",
+			modify: func(req *ReserveLivestreamRequest) {
+				req.Title = "  "
+			},
+			want: map[string]string{"title": "title is required"},
+		},
+		{
+			name: "最大の長さのタイトル",
+			modify: func(req *ReserveLivestreamRequest) {
+				req.Title = strings.Repeat("あ", maxLivestreamTextLength)
+			},
+		},
+		{
+			name: "タイトルの前後に空白",
+			modify: func(req *ReserveLivestreamRequest) {
+				req.Title = "  ゲーム実況配信\n"
+			},
+			want: map[string]string{"title": "title must not have leading or trailing spaces"},
+		},
+		{
+			name: "タイトルが長い",
+			modify: func(req *ReserveLivestreamRequest) {
+				req.Title = strings.Repeat("あ", maxLivestreamTextLength+1)
+			},
+			want: map[string]string{"title": "title must be at most 255 characters"},
+		},
+		{
+			name: "URLの形式",
+			modify: func(req *ReserveLivestreamRequest) {
+				req.PlaylistUrl = "javascript:alert(1)"
+				req.ThumbnailUrl = "/relative.jpg"
+			},
+			want: map[string]string{
+				"playlist_url":  "playlist_url must be an http or https url",
+				"thumbnail_url": "thumbnail_url must be an http or https url",
+			},
+		},
+		{
+			name: "終了が開始より前",
+			modify: func(req *ReserveLivestreamRequest) {
+				req.EndAt = req.StartAt
+			},
+			want: map[string]string{"end_at": "end_at must be after start_at"},
+		},
+		{
+			name: "予約期間の外",
+			modify: func(req *ReserveLivestreamRequest) {
+				req.StartAt = 1732496400
+				req.EndAt = 1732500000
+			},
+			want: map[string]string{"start_at": "start_at must be before 1732496400"},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid()
+			tt.modify(&req)
+			before := req
+			tags, err := req.validate()
+			if !reflect.DeepEqual(req, before) {
+				t.Errorf("validate modified the request: %+v, want %+v", req, before)
+			}
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %+v", err)
+				}
+				if len(tags) != len(req.Tags) {
+					t.Errorf("tags = %v, want %v", tags, req.Tags)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("err = %v, want ValidationError", err)
+			}
+			if !reflect.DeepEqual(verr.Fields, tt.want) {
+				t.Errorf("got: %v, want: %v", verr.Fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorResponseHandlerValidationError(t *testing.T) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/api/livestream/reservation", nil), rec)
+
+	verr := &ValidationError{}
+	verr.add("title", "title is required")
+	errorResponseHandler(verr, c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+	var res ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Fields["title"] != "title is required" {
+		t.Errorf("response = %+v", res)
+	}
+}